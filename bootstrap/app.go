@@ -1,21 +1,28 @@
 package bootstrap
 
 import (
+	"time"
+
 	"github.com/anhvanhoa/service-core/bootstrap/db"
 	"github.com/anhvanhoa/service-core/domain/cache"
 	"github.com/anhvanhoa/service-core/domain/log"
 	q "github.com/anhvanhoa/service-core/domain/queue"
 	"github.com/anhvanhoa/service-core/utils"
 	"github.com/go-pg/pg/v10"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap/zapcore"
 )
 
 type Application struct {
-	Env    *Env
-	DB     *pg.DB
-	Log    *log.LogGRPCImpl
-	Cache  cache.CacheI
+	Env   *Env
+	DB    *pg.DB
+	Log   *log.LogGRPCImpl
+	Cache cache.CacheI
+	// Redis dùng chung server với Cache cho các thao tác nguyên tử (set, hash, SETNX) mà CacheI không có
+	Redis  *redis.Client
 	Queue  q.QueueClient
+	Events *asynq.Client
 	Helper utils.Helper
 }
 
@@ -38,6 +45,15 @@ func App() *Application {
 		env.DbCache.IdleTimeout,
 	)
 	cache := cache.NewCache(configRedis)
+	redisClient := redis.NewClient(&redis.Options{
+		Network:         env.DbCache.Network,
+		Addr:            env.DbCache.Addr,
+		Password:        env.DbCache.Password,
+		DB:              env.DbCache.Db,
+		MaxIdleConns:    env.DbCache.MaxIdle,
+		PoolSize:        env.DbCache.MaxActive,
+		ConnMaxIdleTime: time.Duration(env.DbCache.IdleTimeout) * time.Second,
+	})
 	cfgQueue := q.NewDefaultConfig(
 		env.Queue.Addr,
 		env.Queue.Network,
@@ -47,13 +63,21 @@ func App() *Application {
 		5,
 	)
 	queue := q.NewQueueClient(cfgQueue)
+	events := asynq.NewClient(asynq.RedisClientOpt{
+		Network:  env.Queue.Network,
+		Addr:     env.Queue.Addr,
+		Password: env.Queue.Password,
+		DB:       env.Queue.Db,
+	})
 	helper := utils.NewHelper()
 	return &Application{
 		Env:    &env,
 		DB:     db,
 		Log:    log,
 		Cache:  cache,
+		Redis:  redisClient,
 		Queue:  queue,
+		Events: events,
 		Helper: helper,
 	}
 }
@@ -14,6 +14,14 @@ type jwtSecret struct {
 	Forgot  string `mapstructure:"forgot"`
 }
 
+type argon2 struct {
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
 type dbCache struct {
 	Addr        string `mapstructure:"addr"`
 	Db          int    `mapstructure:"db"`
@@ -36,6 +44,46 @@ type queue struct {
 	Queues      map[string]int `mapstructure:"queues"`
 }
 
+type httpGateway struct {
+	Port int `mapstructure:"port"`
+}
+
+type metrics struct {
+	Port int `mapstructure:"port"`
+}
+
+type janitor struct {
+	Schedule                string `mapstructure:"schedule"`
+	UnverifiedRetentionDays int    `mapstructure:"unverified_retention_days"`
+	UnverifiedReminderDays  int    `mapstructure:"unverified_reminder_days"`
+}
+
+type organization struct {
+	NameScope        string `mapstructure:"name_scope"`
+	SingleMembership bool   `mapstructure:"single_membership"`
+}
+
+type sessionWriter struct {
+	QueueSize         int    `mapstructure:"queue_size"`
+	Workers           int    `mapstructure:"workers"`
+	MaxRetries        int    `mapstructure:"max_retries"`
+	ReconcileInterval string `mapstructure:"reconcile_interval"`
+}
+
+type tracing struct {
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type sessionCookie struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Domain   string `mapstructure:"domain"`
+	Secure   *bool  `mapstructure:"secure"`
+	SameSite string `mapstructure:"same_site"`
+}
+
 type Env struct {
 	NodeEnv               string                    `mapstructure:"node_env"`
 	SecretService         string                    `mapstructure:"secret_service"`
@@ -53,6 +101,23 @@ type Env struct {
 	MailServiceAddr       string                    `mapstructure:"mail_service_addr"`
 	PermissionServiceAddr string                    `mapstructure:"permission_service_addr"`
 	GrpcClients           []*grpc_client.ConfigGrpc `mapstructure:"grpc_clients"`
+	BreachedPasswordIndex string                    `mapstructure:"breached_password_index"`
+	PasswordHistorySize   int                       `mapstructure:"password_history_size"`
+	Argon2                *argon2                   `mapstructure:"argon2"`
+	PasswordExpiryDays    int                       `mapstructure:"password_expiry_days"`
+	AuditRetentionDays    int                       `mapstructure:"auth_event_retention_days"`
+	HttpGateway           *httpGateway              `mapstructure:"http_gateway"`
+	SessionCookie         *sessionCookie            `mapstructure:"session_cookie"`
+	TokenMetadataKey      string                    `mapstructure:"token_metadata_key"`
+	Metrics               *metrics                  `mapstructure:"metrics"`
+	Tracing               *tracing                  `mapstructure:"tracing"`
+	PermissionPolicy      map[string]string         `mapstructure:"permission_policy"`
+	ShutdownTimeout       string                    `mapstructure:"shutdown_timeout"`
+	SessionWriter         *sessionWriter            `mapstructure:"session_writer"`
+	Janitor               *janitor                  `mapstructure:"janitor"`
+	Organization          *organization             `mapstructure:"organization"`
+	InvitationExpiryHours int                       `mapstructure:"invitation_expiry_hours"`
+	TrustedProxies        []string                  `mapstructure:"trusted_proxies"`
 }
 
 func NewEnv(env any) {
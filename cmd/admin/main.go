@@ -0,0 +1,547 @@
+package main
+
+import (
+	"auth-service/bootstrap"
+	"auth-service/constants"
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"auth-service/domain/usecase"
+	"auth-service/infrastructure/accesstoken"
+	"auth-service/infrastructure/event"
+	"auth-service/infrastructure/hasher"
+	"auth-service/infrastructure/job"
+	"auth-service/infrastructure/redisstore"
+	"auth-service/infrastructure/repo"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/transaction"
+	"github.com/hibiken/asynq"
+)
+
+func usage() {
+	fmt.Println("Usage:")
+	fmt.Println("  admin force-password-reset <user-id>...")
+	fmt.Println("  admin password-expiry [-disable] <user-id>...")
+	fmt.Println("  admin suspend-user [-reason <text>] <user-id>...")
+	fmt.Println("  admin delete-user [-reason <text>] <user-id>...")
+	fmt.Println("  admin webhook-create -url <url> -events <type,type|*> [-secret <secret>]")
+	fmt.Println("  admin webhooks")
+	fmt.Println("  admin webhook-delete <subscription-id>")
+	fmt.Println("  admin webhook-deliveries [-sub <id>] [-status pending|delivered|failed] [-page N] [-size N]")
+	fmt.Println("  admin webhook-replay <delivery-id>...")
+	fmt.Println("  admin auth-events [-user <id>] [-type <type>] [-outcome success|failure] [-page N] [-size N]")
+	fmt.Println("  admin invalidate-permissions <user-id>...")
+	fmt.Println("  admin org-create -name <name> -slug <slug> -owner <user-id>")
+	fmt.Println("  admin org-add-member -org <organization-id> -email <email> [-role owner|admin|member] [-actor <user-id>]")
+	fmt.Println("  admin org-switch -user <user-id> -org <organization-id>")
+	fmt.Println("  admin orgs <user-id>")
+	fmt.Println("  admin invite -inviter <user-id> -email <email> [-name <full-name>] [-org <organization-id> [-role owner|admin|member]]")
+	fmt.Println("  admin pat-create -user <user-id> -name <name> -scopes <resource.action,...|*> [-expires <duration>]")
+	fmt.Println("  admin pats <user-id>")
+	fmt.Println("  admin pat-revoke -user <user-id> <token-id>...")
+	fmt.Println("  admin sa-create -name <name> (-user <user-id> | -org <organization-id>) [-description <text>]")
+	fmt.Println("  admin sas [-user <user-id>] [-org <organization-id>]")
+	fmt.Println("  admin sa-disable <service-account-id>...")
+	fmt.Println("  admin sa-enable <service-account-id>...")
+	fmt.Println("  admin sa-secret-create -sa <service-account-id> [-expires <duration>]")
+	fmt.Println("  admin sa-key-add -sa <service-account-id> -pem <public-key-file> [-expires <duration>]")
+	fmt.Println("  admin sa-keys <service-account-id>")
+	fmt.Println("  admin sa-key-revoke -sa <service-account-id> <key-id>...")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	app := bootstrap.App()
+	log := app.Log
+	db := app.DB
+	defer db.Close()
+
+	switch os.Args[1] {
+	case "force-password-reset":
+		ids := os.Args[2:]
+		if len(ids) == 0 {
+			usage()
+			os.Exit(1)
+		}
+		forceResetUc := usecase.NewForcePasswordResetUsecase(
+			repo.NewUserRepository(db),
+			repo.NewSessionRepository(db),
+			transaction.NewTransaction(db),
+			newTokenRevocationUsecase(app),
+		)
+		affected, err := forceResetUc.Execute(context.Background(), ids)
+		if err != nil {
+			log.Fatal("Failed to force password reset: " + err.Error())
+		}
+		log.Info(fmt.Sprintf("Forced password reset for %d account(s)", affected))
+	case "password-expiry":
+		cmd := flag.NewFlagSet("password-expiry", flag.ExitOnError)
+		disable := cmd.Bool("disable", false, "tắt hết hạn mật khẩu")
+		cmd.Parse(os.Args[2:])
+		if cmd.NArg() == 0 {
+			usage()
+			os.Exit(1)
+		}
+		affected, err := usecase.NewPasswordExpiryUsecase(repo.NewUserRepository(db)).
+			SetEnabled(context.Background(), cmd.Args(), !*disable)
+		if err != nil {
+			log.Fatal("Failed to update password expiry: " + err.Error())
+		}
+		if *disable {
+			log.Info(fmt.Sprintf("Disabled password expiry for %d account(s)", affected))
+		} else {
+			log.Info(fmt.Sprintf("Enabled password expiry for %d account(s)", affected))
+		}
+	case "suspend-user", "delete-user":
+		cmd := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+		reason := cmd.String("reason", "", "lý do")
+		cmd.Parse(os.Args[2:])
+		ids := cmd.Args()
+		if len(ids) == 0 {
+			usage()
+			os.Exit(1)
+		}
+		publisher := event.NewOutboxPublisher(repo.NewOutboxRepository(db))
+		userRepo := repo.NewUserRepository(db)
+		tx := transaction.NewTransaction(db)
+		if os.Args[1] == "suspend-user" {
+			suspendUc := usecase.NewSuspendUserUsecase(userRepo, repo.NewSessionRepository(db), tx, publisher, newTokenRevocationUsecase(app))
+			affected, err := suspendUc.Execute(context.Background(), ids, *reason)
+			if err != nil {
+				log.Fatal("Failed to suspend users: " + err.Error())
+			}
+			log.Info(fmt.Sprintf("Suspended %d account(s)", affected))
+			return
+		}
+		deleteUc := usecase.NewDeleteUserUsecase(userRepo, tx, publisher, newTokenRevocationUsecase(app))
+		affected, err := deleteUc.Execute(context.Background(), ids, *reason)
+		if err != nil {
+			log.Fatal("Failed to delete users: " + err.Error())
+		}
+		log.Info(fmt.Sprintf("Deleted %d account(s)", affected))
+	case "auth-events":
+		cmd := flag.NewFlagSet("auth-events", flag.ExitOnError)
+		userID := cmd.String("user", "", "lọc theo user id")
+		eventType := cmd.String("type", "", "lọc theo loại sự kiện")
+		outcome := cmd.String("outcome", "", "lọc theo kết quả")
+		page := cmd.Int("page", 1, "trang")
+		size := cmd.Int("size", 20, "số bản ghi mỗi trang")
+		cmd.Parse(os.Args[2:])
+		authEventUc := usecase.NewAuthEventUsecase(repo.NewAuthEventRepository(db))
+		events, total, err := authEventUc.List(context.Background(), repository.AuthEventFilter{
+			UserID:   *userID,
+			Type:     entity.AuthEventType(*eventType),
+			Outcome:  entity.AuthEventOutcome(*outcome),
+			Page:     *page,
+			PageSize: *size,
+		})
+		if err != nil {
+			log.Fatal("Failed to list auth events: " + err.Error())
+		}
+		for _, e := range events {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				e.CreatedAt.Format(time.RFC3339), e.Type, e.Outcome, e.UserID, e.IP, e.Os, e.UserAgent, e.Reason)
+		}
+		fmt.Printf("Total: %d\n", total)
+	case "webhook-create":
+		cmd := flag.NewFlagSet("webhook-create", flag.ExitOnError)
+		url := cmd.String("url", "", "địa chỉ nhận webhook")
+		events := cmd.String("events", "*", "các loại sự kiện, phân tách bằng dấu phẩy")
+		secret := cmd.String("secret", "", "secret ký HMAC, để trống để sinh ngẫu nhiên")
+		cmd.Parse(os.Args[2:])
+		webhookUc := usecase.NewWebhookUsecase(repo.NewWebhookRepository(db))
+		sub, err := webhookUc.CreateSubscription(context.Background(), usecase.CreateWebhookReq{
+			URL:        *url,
+			Secret:     *secret,
+			EventTypes: strings.Split(*events, ","),
+		})
+		if err != nil {
+			log.Fatal("Failed to create webhook: " + err.Error())
+		}
+		fmt.Printf("ID: %s\nSecret: %s\n", sub.ID, sub.Secret)
+	case "webhooks":
+		webhookUc := usecase.NewWebhookUsecase(repo.NewWebhookRepository(db))
+		subs, err := webhookUc.ListSubscriptions(context.Background())
+		if err != nil {
+			log.Fatal("Failed to list webhooks: " + err.Error())
+		}
+		for _, sub := range subs {
+			fmt.Printf("%s\t%s\t%s\t%t\n", sub.ID, sub.URL, strings.Join(sub.EventTypes, ","), sub.Active)
+		}
+	case "webhook-delete":
+		if len(os.Args) < 3 {
+			usage()
+			os.Exit(1)
+		}
+		webhookUc := usecase.NewWebhookUsecase(repo.NewWebhookRepository(db))
+		if err := webhookUc.DeleteSubscription(context.Background(), os.Args[2]); err != nil {
+			log.Fatal("Failed to delete webhook: " + err.Error())
+		}
+	case "webhook-deliveries":
+		cmd := flag.NewFlagSet("webhook-deliveries", flag.ExitOnError)
+		subID := cmd.String("sub", "", "lọc theo subscription id")
+		state := cmd.String("status", "", "lọc theo trạng thái")
+		page := cmd.Int("page", 1, "trang")
+		size := cmd.Int("size", 20, "số bản ghi mỗi trang")
+		cmd.Parse(os.Args[2:])
+		webhookUc := usecase.NewWebhookUsecase(repo.NewWebhookRepository(db))
+		deliveries, total, err := webhookUc.ListDeliveries(context.Background(), repository.WebhookDeliveryFilter{
+			SubscriptionID: *subID,
+			Status:         entity.WebhookDeliveryStatus(*state),
+			Page:           *page,
+			PageSize:       *size,
+		})
+		if err != nil {
+			log.Fatal("Failed to list webhook deliveries: " + err.Error())
+		}
+		for _, d := range deliveries {
+			fmt.Printf("%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+				d.CreatedAt.Format(time.RFC3339), d.ID, d.EventType, d.Status, d.Attempts, d.ResponseStatus, d.LastError)
+		}
+		fmt.Printf("Total: %d\n", total)
+	case "webhook-replay":
+		ids := os.Args[2:]
+		if len(ids) == 0 {
+			usage()
+			os.Exit(1)
+		}
+		webhookUc := usecase.NewWebhookUsecase(repo.NewWebhookRepository(db))
+		for _, id := range ids {
+			if err := webhookUc.Replay(context.Background(), id); err != nil {
+				log.Fatal("Failed to replay webhook delivery " + id + ": " + err.Error())
+			}
+		}
+		log.Info(fmt.Sprintf("Replayed %d webhook deliveries", len(ids)))
+	case "invalidate-permissions":
+		ids := os.Args[2:]
+		if len(ids) == 0 {
+			usage()
+			os.Exit(1)
+		}
+		defer app.Events.Close()
+		for _, id := range ids {
+			payload, err := json.Marshal(job.PermissionChangedPayload{UserID: id})
+			if err != nil {
+				log.Fatal("Failed to encode payload: " + err.Error())
+			}
+			task := asynq.NewTask(constants.TASK_PERMISSION_USER_CHANGED, payload)
+			if _, err := app.Events.Enqueue(task, asynq.Queue(string(constants.QUEUE_PERMISSION))); err != nil {
+				log.Fatal("Failed to enqueue permission invalidation for " + id + ": " + err.Error())
+			}
+		}
+		log.Info(fmt.Sprintf("Queued permission invalidation for %d user(s)", len(ids)))
+	case "org-create":
+		cmd := flag.NewFlagSet("org-create", flag.ExitOnError)
+		name := cmd.String("name", "", "tên tổ chức")
+		slug := cmd.String("slug", "", "slug tổ chức")
+		owner := cmd.String("owner", "", "user id của owner")
+		cmd.Parse(os.Args[2:])
+		org, err := newOrganizationUsecase(app).Create(context.Background(), usecase.CreateOrganizationReq{
+			Name:    *name,
+			Slug:    *slug,
+			OwnerID: *owner,
+		})
+		if err != nil {
+			log.Fatal("Failed to create organization: " + err.Error())
+		}
+		fmt.Printf("ID: %s\nSlug: %s\n", org.ID, org.Slug)
+	case "org-add-member":
+		cmd := flag.NewFlagSet("org-add-member", flag.ExitOnError)
+		orgID := cmd.String("org", "", "organization id")
+		email := cmd.String("email", "", "email người dùng")
+		role := cmd.String("role", string(entity.OrganizationRoleMember), "vai trò trong tổ chức")
+		actor := cmd.String("actor", "", "user id người thực hiện, để trống để bỏ qua kiểm tra quyền")
+		cmd.Parse(os.Args[2:])
+		member, err := newOrganizationUsecase(app).AddMember(context.Background(), usecase.AddOrganizationMemberReq{
+			OrganizationID: *orgID,
+			ActorID:        *actor,
+			Email:          *email,
+			Role:           entity.OrganizationRole(*role),
+		})
+		if err != nil {
+			log.Fatal("Failed to add organization member: " + err.Error())
+		}
+		log.Info(fmt.Sprintf("Added user %s to organization %s as %s", member.UserID, member.OrganizationID, member.Role))
+	case "org-switch":
+		cmd := flag.NewFlagSet("org-switch", flag.ExitOnError)
+		userID := cmd.String("user", "", "user id")
+		orgID := cmd.String("org", "", "organization id")
+		cmd.Parse(os.Args[2:])
+		if err := newOrganizationUsecase(app).SwitchActive(context.Background(), *userID, *orgID); err != nil {
+			log.Fatal("Failed to switch organization: " + err.Error())
+		}
+		log.Info(fmt.Sprintf("Switched active organization of user %s to %s", *userID, *orgID))
+	case "orgs":
+		if len(os.Args) < 3 {
+			usage()
+			os.Exit(1)
+		}
+		organizationUc := newOrganizationUsecase(app)
+		memberships, err := organizationUc.ListMemberships(context.Background(), os.Args[2])
+		if err != nil {
+			log.Fatal("Failed to list organizations: " + err.Error())
+		}
+		active, err := organizationUc.ActiveOrganization(context.Background(), os.Args[2])
+		if err != nil {
+			log.Fatal("Failed to get active organization: " + err.Error())
+		}
+		for _, m := range memberships {
+			fmt.Printf("%s\t%s\t%t\n", m.OrganizationID, m.Role, m.OrganizationID == active)
+		}
+	case "invite":
+		cmd := flag.NewFlagSet("invite", flag.ExitOnError)
+		inviter := cmd.String("inviter", "", "user id người mời")
+		email := cmd.String("email", "", "email người được mời")
+		name := cmd.String("name", "", "họ tên người được mời")
+		orgID := cmd.String("org", "", "mời vào tổ chức")
+		role := cmd.String("role", "", "vai trò gợi ý trong tổ chức")
+		cmd.Parse(os.Args[2:])
+		invitation, token, err := newInvitationUsecase(app).Invite(context.Background(), usecase.InviteReq{
+			InviterID:      *inviter,
+			Email:          *email,
+			FullName:       *name,
+			OrganizationID: *orgID,
+			Role:           entity.OrganizationRole(*role),
+		})
+		if err != nil {
+			log.Fatal("Failed to invite user: " + err.Error())
+		}
+		fmt.Printf("ID: %s\nToken: %s\nExpires: %s\n", invitation.ID, token, invitation.ExpiresAt.Format(time.RFC3339))
+	case "pat-create":
+		cmd := flag.NewFlagSet("pat-create", flag.ExitOnError)
+		userID := cmd.String("user", "", "user id chủ token")
+		name := cmd.String("name", "", "tên token")
+		scopes := cmd.String("scopes", "", "các scope, phân tách bằng dấu phẩy")
+		expires := cmd.Duration("expires", 0, "thời hạn token, 0 là không hết hạn")
+		cmd.Parse(os.Args[2:])
+		req := usecase.CreatePersonalAccessTokenReq{
+			UserID: *userID,
+			Name:   *name,
+			Scopes: strings.Split(*scopes, ","),
+		}
+		if *expires > 0 {
+			exp := time.Now().Add(*expires)
+			req.ExpiresAt = &exp
+		}
+		tokenUc := usecase.NewPersonalAccessTokenUsecase(repo.NewPersonalAccessTokenRepository(db), repo.NewUserRepository(db), app.Cache)
+		pat, token, err := tokenUc.Create(context.Background(), req)
+		if err != nil {
+			log.Fatal("Failed to create personal access token: " + err.Error())
+		}
+		fmt.Printf("ID: %s\nToken: %s\n", pat.ID, token)
+	case "pats":
+		if len(os.Args) < 3 {
+			usage()
+			os.Exit(1)
+		}
+		tokenUc := usecase.NewPersonalAccessTokenUsecase(repo.NewPersonalAccessTokenRepository(db), repo.NewUserRepository(db), app.Cache)
+		tokens, err := tokenUc.List(context.Background(), os.Args[2])
+		if err != nil {
+			log.Fatal("Failed to list personal access tokens: " + err.Error())
+		}
+		for _, t := range tokens {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				t.ID, t.Name, t.Prefix, strings.Join(t.Scopes, ","), formatTime(t.ExpiresAt), formatTime(t.LastUsedAt), formatTime(t.RevokedAt))
+		}
+	case "pat-revoke":
+		cmd := flag.NewFlagSet("pat-revoke", flag.ExitOnError)
+		userID := cmd.String("user", "", "user id chủ token")
+		cmd.Parse(os.Args[2:])
+		ids := cmd.Args()
+		if len(ids) == 0 {
+			usage()
+			os.Exit(1)
+		}
+		tokenUc := usecase.NewPersonalAccessTokenUsecase(repo.NewPersonalAccessTokenRepository(db), repo.NewUserRepository(db), app.Cache)
+		for _, id := range ids {
+			if err := tokenUc.Revoke(context.Background(), *userID, id); err != nil {
+				log.Fatal("Failed to revoke personal access token " + id + ": " + err.Error())
+			}
+		}
+		log.Info(fmt.Sprintf("Revoked %d personal access token(s)", len(ids)))
+	case "sa-create":
+		cmd := flag.NewFlagSet("sa-create", flag.ExitOnError)
+		name := cmd.String("name", "", "tên service account")
+		description := cmd.String("description", "", "mô tả")
+		userID := cmd.String("user", "", "user id chủ sở hữu")
+		orgID := cmd.String("org", "", "organization id chủ sở hữu")
+		cmd.Parse(os.Args[2:])
+		account, err := newServiceAccountUsecase(app).Create(context.Background(), usecase.CreateServiceAccountReq{
+			Name:                *name,
+			Description:         *description,
+			OwnerUserID:         *userID,
+			OwnerOrganizationID: *orgID,
+		})
+		if err != nil {
+			log.Fatal("Failed to create service account: " + err.Error())
+		}
+		fmt.Printf("ID: %s\nEmail: %s\n", account.ID, account.Email())
+	case "sas":
+		cmd := flag.NewFlagSet("sas", flag.ExitOnError)
+		userID := cmd.String("user", "", "lọc theo user id chủ sở hữu")
+		orgID := cmd.String("org", "", "lọc theo organization id chủ sở hữu")
+		cmd.Parse(os.Args[2:])
+		accounts, err := newServiceAccountUsecase(app).List(context.Background(), repository.ServiceAccountFilter{
+			OwnerUserID:         *userID,
+			OwnerOrganizationID: *orgID,
+		})
+		if err != nil {
+			log.Fatal("Failed to list service accounts: " + err.Error())
+		}
+		for _, a := range accounts {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", a.ID, a.Name, a.OwnerUserID, a.OwnerOrganizationID, formatTime(a.DisabledAt))
+		}
+	case "sa-disable", "sa-enable":
+		ids := os.Args[2:]
+		if len(ids) == 0 {
+			usage()
+			os.Exit(1)
+		}
+		serviceAccountUc := newServiceAccountUsecase(app)
+		for _, id := range ids {
+			if err := serviceAccountUc.SetDisabled(context.Background(), "", id, os.Args[1] == "sa-disable"); err != nil {
+				log.Fatal("Failed to update service account " + id + ": " + err.Error())
+			}
+		}
+		log.Info(fmt.Sprintf("Updated %d service account(s)", len(ids)))
+	case "sa-secret-create", "sa-key-add":
+		cmd := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+		saID := cmd.String("sa", "", "service account id")
+		pemFile := cmd.String("pem", "", "file public key dạng PEM")
+		expires := cmd.Duration("expires", 0, "thời hạn khóa, 0 là không hết hạn")
+		cmd.Parse(os.Args[2:])
+		var expiresAt *time.Time
+		if *expires > 0 {
+			exp := time.Now().Add(*expires)
+			expiresAt = &exp
+		}
+		serviceAccountUc := newServiceAccountUsecase(app)
+		if os.Args[1] == "sa-secret-create" {
+			key, secret, err := serviceAccountUc.CreateSecret(context.Background(), "", *saID, expiresAt)
+			if err != nil {
+				log.Fatal("Failed to create service account secret: " + err.Error())
+			}
+			fmt.Printf("Key ID: %s\nSecret: %s\n", key.ID, secret)
+			return
+		}
+		publicKey, err := os.ReadFile(*pemFile)
+		if err != nil {
+			log.Fatal("Failed to read public key: " + err.Error())
+		}
+		key, err := serviceAccountUc.AddPublicKey(context.Background(), "", *saID, string(publicKey), expiresAt)
+		if err != nil {
+			log.Fatal("Failed to add service account key: " + err.Error())
+		}
+		fmt.Printf("Key ID: %s\n", key.ID)
+	case "sa-keys":
+		if len(os.Args) < 3 {
+			usage()
+			os.Exit(1)
+		}
+		keys, err := newServiceAccountUsecase(app).ListKeys(context.Background(), "", os.Args[2])
+		if err != nil {
+			log.Fatal("Failed to list service account keys: " + err.Error())
+		}
+		for _, k := range keys {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", k.ID, k.Type, formatTime(k.ExpiresAt), formatTime(k.LastUsedAt), formatTime(k.RevokedAt))
+		}
+	case "sa-key-revoke":
+		cmd := flag.NewFlagSet("sa-key-revoke", flag.ExitOnError)
+		saID := cmd.String("sa", "", "service account id")
+		cmd.Parse(os.Args[2:])
+		ids := cmd.Args()
+		if len(ids) == 0 {
+			usage()
+			os.Exit(1)
+		}
+		serviceAccountUc := newServiceAccountUsecase(app)
+		for _, id := range ids {
+			if err := serviceAccountUc.RevokeKey(context.Background(), "", *saID, id); err != nil {
+				log.Fatal("Failed to revoke service account key " + id + ": " + err.Error())
+			}
+		}
+		log.Info(fmt.Sprintf("Revoked %d service account key(s)", len(ids)))
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func newOrganizationUsecase(app *bootstrap.Application) usecase.OrganizationUsecase {
+	var policy usecase.OrganizationPolicy
+	if app.Env.Organization != nil {
+		policy = usecase.OrganizationPolicy{
+			NameScope:        usecase.OrganizationNameScope(app.Env.Organization.NameScope),
+			SingleMembership: app.Env.Organization.SingleMembership,
+		}
+	}
+	return usecase.NewOrganizationUsecase(
+		repo.NewOrganizationRepository(app.DB),
+		repo.NewUserRepository(app.DB),
+		transaction.NewTransaction(app.DB),
+		policy,
+	)
+}
+
+func newInvitationUsecase(app *bootstrap.Application) usecase.InvitationUsecase {
+	breachedPasswordRepo, err := repo.NewBreachedPasswordRepository(app.Env.BreachedPasswordIndex)
+	if err != nil {
+		app.Log.Fatal("Failed to load breached password index: " + err.Error())
+	}
+	argonService := hasher.NewHasher(hasher.ParamsFromEnv(app.Env))
+	return usecase.NewInvitationUsecase(
+		repo.NewInvitationRepository(app.DB),
+		repo.NewUserRepository(app.DB),
+		repo.NewOrganizationRepository(app.DB),
+		repo.NewOutboxRepository(app.DB),
+		transaction.NewTransaction(app.DB),
+		argonService,
+		usecase.NewPasswordPolicyUsecase(breachedPasswordRepo),
+		usecase.NewPasswordHistoryUsecase(
+			repo.NewPasswordHistoryRepository(app.DB),
+			argonService,
+			app.Env.PasswordHistorySize,
+		),
+		event.NewOutboxPublisher(repo.NewOutboxRepository(app.DB)),
+		usecase.InvitationPolicy{
+			Lifetime:   time.Duration(app.Env.InvitationExpiryHours) * time.Hour,
+			AcceptLink: app.Env.FrontendUrl + "/auth/invitation/",
+		},
+	)
+}
+
+func newTokenRevocationUsecase(app *bootstrap.Application) usecase.TokenRevocationUsecase {
+	return usecase.NewTokenRevocationUsecase(
+		app.Cache,
+		redisstore.NewTokenIndex(app.Redis, constants.KeyCacheRefreshTokens),
+		redisstore.NewTokenIndex(app.Redis, constants.KeyCachePermissionTokens),
+	)
+}
+
+func newServiceAccountUsecase(app *bootstrap.Application) usecase.ServiceAccountUsecase {
+	return usecase.NewServiceAccountUsecase(
+		repo.NewServiceAccountRepository(app.DB),
+		repo.NewUserRepository(app.DB),
+		repo.NewOrganizationRepository(app.DB),
+		accesstoken.NewIssuer(app.Env.JwtSecret.Access),
+		accesstoken.NewClientAssertionVerifier(app.Env.NameService),
+		redisstore.NewNonceStore(app.Redis, constants.KeyCacheServiceAccountAssertions),
+	)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -2,11 +2,46 @@ package main
 
 import (
 	"auth-service/bootstrap"
+	"auth-service/constants"
+	"auth-service/domain/usecase"
+	"auth-service/infrastructure/accesstoken"
+	"auth-service/infrastructure/event"
 	"auth-service/infrastructure/grpc_client"
 	grpcservice "auth-service/infrastructure/grpc_service"
+	"auth-service/infrastructure/hasher"
+	"auth-service/infrastructure/health"
+	httpgateway "auth-service/infrastructure/http_gateway"
+	"auth-service/infrastructure/job"
+	"auth-service/infrastructure/metrics"
+	"auth-service/infrastructure/redisstore"
+	"auth-service/infrastructure/repo"
+	"auth-service/infrastructure/tracing"
+	"auth-service/infrastructure/webhook"
+	"auth-service/infrastructure/worker"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/anhvanhoa/service-core/domain/cache"
 	gc "github.com/anhvanhoa/service-core/domain/grpc_client"
+	"github.com/anhvanhoa/service-core/domain/log"
+	"github.com/anhvanhoa/service-core/domain/queue"
+	"github.com/anhvanhoa/service-core/domain/saga"
+	"github.com/anhvanhoa/service-core/domain/token"
+	"github.com/anhvanhoa/service-core/domain/transaction"
+	"github.com/go-pg/pg/v10"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultShutdownTimeout   = 30 * time.Second
+	defaultReconcileInterval = 5 * time.Minute
 )
 
 func main() {
@@ -16,23 +51,302 @@ func main() {
 	db := app.DB
 	cache := app.Cache
 	queueClient := app.Queue
+	db.AddQueryHook(metrics.QueryHook{})
+	db.AddQueryHook(tracing.QueryHook{})
 
 	clientFactory := gc.NewClientFactory(env.GrpcClients...)
-	mailService := grpc_client.NewMailService(clientFactory.GetClient(env.MailServiceAddr))
-	permissionClient, err := grpc_client.NewPermissionClient(clientFactory.GetClient(env.PermissionServiceAddr))
-	if err != nil {
-		log.Error("Failed to create permission client: " + err.Error())
+	mailClient := clientFactory.GetClient(env.MailServiceAddr)
+	permissionConn := clientFactory.GetClient(env.PermissionServiceAddr)
+	mailService := grpc_client.NewMailService(mailClient)
+	dialPermission := func() (grpc_client.PermissionClient, error) {
+		return grpc_client.NewPermissionClient(clientFactory.GetClient(env.PermissionServiceAddr))
 	}
 
-	authService := grpcservice.NewAuthService(db, env, log, mailService, permissionClient, queueClient, cache)
-	grpcSrv := grpcservice.NewGRPCServer(env, cache, log, authService)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	healthChecker := health.NewChecker(env, log)
+	healthChecker.AddProbe(health.ComponentPostgres, health.PostgresProbe(db))
+	healthChecker.AddProbe(health.ComponentCache, health.CacheProbe(cache))
+	healthChecker.AddProbe(health.ComponentQueue, health.QueueProbe(app.Events))
+	healthChecker.AddProbe(health.ComponentMail, health.GrpcClientProbe(mailClient))
+	healthChecker.AddProbe(health.ComponentPermission, health.GrpcClientProbe(permissionConn))
+
+	accessTokenIndex := redisstore.NewTokenIndex(app.Redis, constants.KeyCachePermissionTokens)
+	refreshTokenIndex := redisstore.NewTokenIndex(app.Redis, constants.KeyCacheRefreshTokens)
+	permissionCache := grpcservice.NewPermissionCache(
+		cache,
+		accessTokenIndex,
+		grpc_client.NewPermissionResolver(dialPermission, cache, env.PermissionPolicy),
+	)
+	workers := worker.NewGroup(log)
+	drainer := grpcservice.NewDrainer()
+	var writerConfig repo.WriteBehindConfig
+	reconcileInterval := defaultReconcileInterval
+	if env.SessionWriter != nil {
+		writerConfig = repo.WriteBehindConfig{
+			QueueSize:  env.SessionWriter.QueueSize,
+			Workers:    env.SessionWriter.Workers,
+			MaxRetries: env.SessionWriter.MaxRetries,
+		}
+		reconcileInterval = parseDuration(env.SessionWriter.ReconcileInterval, defaultReconcileInterval)
+	}
+	sessionRepo := repo.NewWriteBehindSessionRepository(
+		repo.NewSessionRepository(db),
+		cache,
+		redisstore.NewFailedSessionStore(app.Redis, constants.KeyCacheSessionWriteFailed),
+		writerConfig,
+		log,
+	)
+	revocationUc := usecase.NewTokenRevocationUsecase(cache, refreshTokenIndex, accessTokenIndex)
+	notMeUc := usecase.NewNotMeUsecase(
+		repo.NewUserRepository(db),
+		sessionRepo,
+		cache,
+		usecase.NewForcePasswordResetUsecase(repo.NewUserRepository(db), sessionRepo, transaction.NewTransaction(db), revocationUc),
+		usecase.NewForgotPasswordUsecase(
+			repo.NewUserRepository(db),
+			sessionRepo,
+			transaction.NewTransaction(db),
+			token.NewToken(env.JwtSecret.Forgot),
+			cache,
+			repo.NewOutboxRepository(db),
+			saga.NewSagaManager(),
+			log,
+			workers,
+		),
+	)
+	authService := grpcservice.NewAuthService(
+		db, env, log, mailService, permissionCache, queueClient, cache, healthChecker, workers,
+		sessionRepo, refreshTokenIndex, revocationUc, notMeUc,
+	)
+	accessTokens := accesstoken.NewIssuer(env.JwtSecret.Access)
+	personalAccessTokenUc := usecase.NewPersonalAccessTokenUsecase(repo.NewPersonalAccessTokenRepository(db), repo.NewUserRepository(db), cache)
+	serviceAccountUc := usecase.NewServiceAccountUsecase(
+		repo.NewServiceAccountRepository(db),
+		repo.NewUserRepository(db),
+		repo.NewOrganizationRepository(db),
+		accessTokens,
+		accesstoken.NewClientAssertionVerifier(env.NameService),
+		redisstore.NewNonceStore(app.Redis, constants.KeyCacheServiceAccountAssertions),
+	)
+	userContexts := grpcservice.NewUserContextResolver(
+		cache,
+		permissionCache,
+		personalAccessTokenUc,
+		serviceAccountUc,
+		accessTokens,
+		log,
+	)
+	grpcSrv := grpcservice.NewGRPCServer(env, cache, log, authService, healthChecker, drainer, userContexts)
+	webhookRepo := repo.NewWebhookRepository(db)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownTracing, err := tracing.Init(ctx, env)
+	if err != nil {
+		log.Error("Failed to init tracing: " + err.Error())
+	}
+	healthChecker.Start(ctx)
+	authEventUc := usecase.NewAuthEventUsecase(repo.NewAuthEventRepository(db))
+	janitorConfig := job.JanitorConfig{
+		AuditRetention: time.Duration(env.AuditRetentionDays) * 24 * time.Hour,
+	}
+	unverifiedPolicy := usecase.UnverifiedAccountPolicy{
+		VerifyLink: env.FrontendUrl + "/auth/verify/",
+	}
+	if env.Janitor != nil {
+		janitorConfig.Schedule = env.Janitor.Schedule
+		unverifiedPolicy.Retention = time.Duration(env.Janitor.UnverifiedRetentionDays) * 24 * time.Hour
+		unverifiedPolicy.ReminderLead = time.Duration(env.Janitor.UnverifiedReminderDays) * 24 * time.Hour
+	}
+	job.NewJanitorJob(
+		janitorConfig,
+		repo.NewAdvisoryLocker(db),
+		usecase.NewJanitorUsecase(sessionRepo, repo.NewInvitationRepository(db)),
+		authEventUc,
+		usecase.NewUnverifiedAccountUsecase(
+			repo.NewUserRepository(db),
+			sessionRepo,
+			repo.NewOutboxRepository(db),
+			transaction.NewTransaction(db),
+			token.NewToken(env.JwtSecret.Verify),
+			cache,
+			unverifiedPolicy,
+		),
+		log,
+	).Start(ctx, workers)
+	job.NewOutboxRelayJob(
+		usecase.NewOutboxRelayUsecase(
+			repo.NewOutboxRepository(db),
+			grpc_client.NewMailSender(mailService, app.Events),
+			event.NewMultiPublisher(
+				event.NewAsynqPublisher(app.Events, log),
+				event.NewWebhookPublisher(webhookRepo),
+			),
+		),
+		log,
+	).Start(ctx, workers)
+	job.NewPermissionInvalidationJob(env, permissionCache, log).Start(ctx, workers)
+	job.NewSessionReconcileJob(sessionRepo, reconcileInterval, log).Start(ctx, workers)
+	job.NewWebhookDispatchJob(
+		usecase.NewWebhookDispatchUsecase(webhookRepo, webhook.NewSender(nil)),
+		log,
+	).Start(ctx, workers)
 	permissions := app.Helper.ConvertResourcesToPermissions(grpcSrv.GetResources())
-	if _, err := permissionClient.PermissionService().RegisterPermission(ctx, permissions); err != nil {
-		log.Error("Failed to register permission: " + err.Error())
+	job.NewPermissionRegistrationJob(func(ctx context.Context) error {
+		client, err := dialPermission()
+		if err != nil {
+			return err
+		}
+		_, err = client.PermissionService().RegisterPermission(ctx, permissions)
+		return err
+	}, log).Start(ctx, workers)
+	if env.Metrics != nil && env.Metrics.Port != 0 {
+		workers.Go("metrics_server", func(context.Context) error {
+			return metrics.NewServer(env.Metrics.Port, log).Start(ctx)
+		})
+	}
+	if env.HttpGateway != nil && env.HttpGateway.Port != 0 {
+		breachedPasswordRepo, err := repo.NewBreachedPasswordRepository(env.BreachedPasswordIndex)
+		if err != nil {
+			log.Fatal("Failed to load breached password index: " + err.Error())
+		}
+		argonService := hasher.NewHasher(hasher.ParamsFromEnv(env))
+		invitationUc := usecase.NewInvitationUsecase(
+			repo.NewInvitationRepository(db),
+			repo.NewUserRepository(db),
+			repo.NewOrganizationRepository(db),
+			repo.NewOutboxRepository(db),
+			transaction.NewTransaction(db),
+			argonService,
+			usecase.NewPasswordPolicyUsecase(breachedPasswordRepo),
+			usecase.NewPasswordHistoryUsecase(repo.NewPasswordHistoryRepository(db), argonService, env.PasswordHistorySize),
+			event.NewOutboxPublisher(repo.NewOutboxRepository(db)),
+			usecase.InvitationPolicy{
+				Lifetime:   time.Duration(env.InvitationExpiryHours) * time.Hour,
+				AcceptLink: env.FrontendUrl + "/auth/invitation/",
+			},
+		)
+		gateway, err := httpgateway.NewGateway(
+			env,
+			log,
+			notMeUc,
+			invitationUc,
+			serviceAccountUc,
+			grpcservice.NewCallerAuthenticator(env.TokenMetadataKey, userContexts),
+			personalAccessTokenUc,
+			usecase.NewOrganizationUsecase(
+				repo.NewOrganizationRepository(db),
+				repo.NewUserRepository(db),
+				transaction.NewTransaction(db),
+				organizationPolicy(env),
+			),
+			authEventUc,
+		)
+		if err != nil {
+			log.Fatal("Failed to create HTTP gateway: " + err.Error())
+		}
+		workers.Go("http_gateway", func(context.Context) error {
+			return gateway.Start(ctx)
+		})
+	}
+
+	// Server gRPC có context riêng để chỉ dừng sau khi các RPC đang xử lý đã xong
+	srvCtx, stopSrv := context.WithCancel(context.Background())
+	srvErr := make(chan error, 1)
+	go func() {
+		srvErr <- grpcSrv.Start(srvCtx)
+	}()
+	var startErr error
+	select {
+	case <-ctx.Done():
+		log.Info("Received shutdown signal, draining in-flight requests")
+	case startErr = <-srvErr:
+		if startErr == nil {
+			startErr = errServerStopped
+		}
+	}
+	stop()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), parseDuration(env.ShutdownTimeout, defaultShutdownTimeout))
+	defer cancelShutdown()
+	healthChecker.Shutdown()
+	if err := drainer.Drain(shutdownCtx); err != nil {
+		log.Error("Timed out draining in-flight RPCs: " + err.Error())
+	}
+	stopSrv()
+	if startErr == nil {
+		select {
+		case <-srvErr:
+		case <-shutdownCtx.Done():
+		}
+	}
+	// workers gồm cả các job nền, chờ chúng dừng hẳn trước khi đóng DB, Redis và queue
+	if err := workers.Wait(shutdownCtx); err != nil {
+		log.Error("Timed out waiting for background tasks: " + err.Error())
+	}
+	if err := sessionRepo.Close(shutdownCtx); err != nil {
+		log.Error("Timed out flushing session writes: " + err.Error())
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("Failed to flush traces: " + err.Error())
+	}
+	closeResources(log, app.Events, queueClient, cache, app.Redis, db, mailClient, permissionConn)
+	if startErr != nil {
+		log.Fatal("gRPC server error: " + startErr.Error())
+	}
+	log.Info("Shutdown complete")
+}
+
+func organizationPolicy(env *bootstrap.Env) usecase.OrganizationPolicy {
+	if env.Organization == nil {
+		return usecase.OrganizationPolicy{}
+	}
+	return usecase.OrganizationPolicy{
+		NameScope:        usecase.OrganizationNameScope(env.Organization.NameScope),
+		SingleMembership: env.Organization.SingleMembership,
+	}
+}
+
+var errServerStopped = errors.New("gRPC server stopped unexpectedly")
+
+func parseDuration(s string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// closeResources đóng các kết nối sau khi worker đã dừng, DB đóng cuối cùng
+func closeResources(
+	log *log.LogGRPCImpl,
+	events *asynq.Client,
+	queueClient queue.QueueClient,
+	cache cache.CacheI,
+	redisClient *redis.Client,
+	db *pg.DB,
+	clients ...*gc.Client,
+) {
+	closeIf := func(name string, v any) {
+		c, ok := v.(io.Closer)
+		if !ok {
+			return
+		}
+		if err := c.Close(); err != nil {
+			log.Error(fmt.Sprintf("Failed to close %s: %s", name, err.Error()))
+		}
+	}
+	closeIf("events", events)
+	closeIf("queue", queueClient)
+	closeIf("cache", cache)
+	closeIf("redis", redisClient)
+	for _, client := range clients {
+		if client == nil || client.GetConnection() == nil {
+			continue
+		}
+		if err := client.GetConnection().Close(); err != nil {
+			log.Error("Failed to close gRPC client: " + err.Error())
+		}
 	}
-	if err := grpcSrv.Start(ctx); err != nil {
-		log.Fatal("gRPC server error: " + err.Error())
+	if err := db.Close(); err != nil {
+		log.Error("Failed to close database: " + err.Error())
 	}
 }
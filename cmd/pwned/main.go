@@ -0,0 +1,73 @@
+package main
+
+import (
+	"auth-service/infrastructure/pwned"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func usage() {
+	fmt.Println("Usage:")
+	fmt.Println("  pwned build  -src <hibp.txt> -out <index>   [-min-count N]")
+	fmt.Println("  pwned update -src <hibp.txt> -index <index> [-min-count N]")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "build":
+		cmd := flag.NewFlagSet("build", flag.ExitOnError)
+		src := cmd.String("src", "", "file HIBP (SHA-1, sắp xếp theo hash)")
+		out := cmd.String("out", "breached.idx", "đường dẫn file index")
+		minCount := cmd.Int("min-count", 1, "bỏ qua hash xuất hiện ít hơn N lần")
+		cmd.Parse(os.Args[2:])
+		runBuild(*src, *out, *minCount)
+	case "update":
+		cmd := flag.NewFlagSet("update", flag.ExitOnError)
+		src := cmd.String("src", "", "file HIBP (SHA-1, sắp xếp theo hash)")
+		index := cmd.String("index", "breached.idx", "đường dẫn file index hiện có")
+		minCount := cmd.Int("min-count", 1, "bỏ qua hash xuất hiện ít hơn N lần")
+		cmd.Parse(os.Args[2:])
+		runUpdate(*src, *index, *minCount)
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func runBuild(src, out string, minCount int) {
+	f := openSource(src)
+	defer f.Close()
+	count, err := pwned.BuildIndex(f, out, minCount)
+	if err != nil {
+		log.Fatalf("Build index failed: %v", err)
+	}
+	fmt.Printf("Built %s with %d hashes\n", out, count)
+}
+
+func runUpdate(src, index string, minCount int) {
+	f := openSource(src)
+	defer f.Close()
+	count, err := pwned.MergeIndex(f, index, minCount)
+	if err != nil {
+		log.Fatalf("Update index failed: %v", err)
+	}
+	fmt.Printf("Updated %s, now %d hashes\n", index, count)
+}
+
+func openSource(src string) *os.File {
+	if src == "" {
+		log.Fatal("Missing -src")
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		log.Fatalf("Open source failed: %v", err)
+	}
+	return f
+}
@@ -0,0 +1,12 @@
+package constants
+
+const (
+	// Chỉ mục user -> access token đang cache quyền
+	KeyCachePermissionTokens = "permission_tokens:"
+	// Chỉ mục user -> refresh token đang cache, dùng để thu hồi mọi phiên của user
+	KeyCacheRefreshTokens = "refresh_tokens:"
+	// Hash các session ghi xuống DB lỗi, chờ job đối soát ghi lại
+	KeyCacheSessionWriteFailed = "failed_sessions"
+	// jti của client assertion đã dùng, mỗi assertion chỉ đổi được một token
+	KeyCacheServiceAccountAssertions = "sa_assertion:"
+)
@@ -3,6 +3,7 @@ package constants
 const (
 	KeyCookieRefreshToken = "rt"
 	KeyCookieAccessToken  = "at"
+	KeyCookieCsrfToken    = "csrf"
 )
 
 const (
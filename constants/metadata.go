@@ -0,0 +1,9 @@
+package constants
+
+const (
+	HeaderPasswordExpired     = "x-password-expired"
+	HeaderPasswordChangeToken = "x-password-change-token"
+	HeaderCsrfToken           = "x-csrf-token"
+	HeaderSetCookie           = "set-cookie"
+	HeaderCookie              = "cookie"
+)
@@ -3,5 +3,10 @@ package constants
 type QueueType string
 
 const (
-	QUEUE_MAIL QueueType = "mail"
+	QUEUE_MAIL  QueueType = "mail"
+	QUEUE_EVENT QueueType = "event"
+	// Permission service đẩy task vào queue này khi vai trò/quyền của người dùng thay đổi
+	QUEUE_PERMISSION QueueType = "permission"
 )
+
+const TASK_PERMISSION_USER_CHANGED = "permission.user_changed"
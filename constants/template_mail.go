@@ -1,7 +1,10 @@
 package constants
 
 const (
-	TPL_REGISTER_MAIL = "register_mail"
-	TPL_FORGOT_MAIL   = "forgot_mail"
-	TPL_VERIFY_MAIL   = "verify_mail"
+	TPL_REGISTER_MAIL        = "register_mail"
+	TPL_FORGOT_MAIL          = "forgot_mail"
+	TPL_VERIFY_MAIL          = "verify_mail"
+	TPL_DEVICE_MAIL          = "new_device_mail"
+	TPL_VERIFY_REMINDER_MAIL = "verify_reminder_mail"
+	TPL_INVITATION_MAIL      = "invitation_mail"
 )
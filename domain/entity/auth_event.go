@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type AuthEventType string
+
+const (
+	AuthEventRegister      AuthEventType = "register"
+	AuthEventLogin         AuthEventType = "login"
+	AuthEventLogout        AuthEventType = "logout"
+	AuthEventRefreshToken  AuthEventType = "refresh_token"
+	AuthEventVerifyAccount AuthEventType = "verify_account"
+	AuthEventForgotPass    AuthEventType = "forgot_password"
+	AuthEventResetPass     AuthEventType = "reset_password"
+	AuthEventCheckToken    AuthEventType = "check_token"
+	AuthEventCheckCode     AuthEventType = "check_code"
+	AuthEventProfile       AuthEventType = "profile"
+)
+
+type AuthEventOutcome string
+
+const (
+	AuthEventSuccess AuthEventOutcome = "success"
+	AuthEventFailure AuthEventOutcome = "failure"
+)
+
+type AuthEvent struct {
+	tableName struct{}         `pg:"auth_events,alias:ae"`
+	ID        int64            `pg:"id,pk"`
+	Type      AuthEventType    `pg:"event_type"`
+	UserID    string           `pg:"user_id"`
+	IP        string           `pg:"ip"`
+	UserAgent string           `pg:"user_agent"`
+	Os        string           `pg:"os"`
+	Outcome   AuthEventOutcome `pg:"outcome"`
+	Reason    string           `pg:"reason"`
+	CreatedAt time.Time        `pg:"created_at"`
+}
+
+func (e *AuthEvent) NameTable() any {
+	return e.tableName
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Invitation gắn với một tài khoản được tạo sẵn (chưa có mật khẩu, chưa xác thực),
+// người được mời đặt mật khẩu khi chấp nhận lời mời
+type Invitation struct {
+	tableName      struct{}         `pg:"invitations,alias:inv"`
+	ID             string           `pg:"id,pk"`
+	Email          string           `pg:"email"`
+	TokenHash      string           `pg:"token_hash,unique"`
+	UserID         string           `pg:"user_id"`
+	InvitedBy      string           `pg:"invited_by"`
+	OrganizationID string           `pg:"organization_id"`
+	Role           OrganizationRole `pg:"role"`
+	ExpiresAt      time.Time        `pg:"expires_at"`
+	AcceptedAt     *time.Time       `pg:"accepted_at"`
+	CreatedAt      time.Time        `pg:"created_at"`
+	UpdatedAt      *time.Time       `pg:"updated_at"`
+}
+
+func (i *Invitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"
+	OrganizationRoleAdmin  OrganizationRole = "admin"
+	OrganizationRoleMember OrganizationRole = "member"
+)
+
+func (r OrganizationRole) IsValid() bool {
+	return slices.Contains([]OrganizationRole{OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleMember}, r)
+}
+
+// CanManageMembers cho biết vai trò có được thêm thành viên vào tổ chức không
+func (r OrganizationRole) CanManageMembers() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// NameKey là khóa duy nhất của tên theo phạm vi cấu hình, index unique trên name_key chặn hai request tạo trùng tên
+type Organization struct {
+	tableName struct{}   `pg:"organizations,alias:o"`
+	ID        string     `pg:"id,pk"`
+	Name      string     `pg:"name"`
+	NameKey   string     `pg:"name_key,unique"`
+	Slug      string     `pg:"slug,unique"`
+	CreatedBy string     `pg:"created_by"`
+	CreatedAt time.Time  `pg:"created_at"`
+	UpdatedAt *time.Time `pg:"updated_at"`
+}
+
+type OrganizationMember struct {
+	tableName      struct{}         `pg:"organization_members,alias:om"`
+	OrganizationID string           `pg:"organization_id,pk"`
+	UserID         string           `pg:"user_id,pk"`
+	Role           OrganizationRole `pg:"role"`
+	CreatedAt      time.Time        `pg:"created_at"`
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxKind string
+
+const (
+	OutboxKindMail  OutboxKind = "mail"
+	OutboxKindEvent OutboxKind = "event"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxDelivered OutboxStatus = "delivered"
+	// OutboxFailed là trạng thái dead-letter sau khi đã hết số lần thử
+	OutboxFailed OutboxStatus = "failed"
+)
+
+type OutboxMessage struct {
+	tableName     struct{}        `pg:"outbox,alias:ob"`
+	ID            string          `pg:"id,pk"`
+	Kind          OutboxKind      `pg:"kind"`
+	Topic         string          `pg:"topic"`
+	Payload       json.RawMessage `pg:"payload,type:jsonb"`
+	Status        OutboxStatus    `pg:"status"`
+	Attempts      int             `pg:"attempts"`
+	TaskID        string          `pg:"task_id"`
+	LastError     string          `pg:"last_error"`
+	NextAttemptAt time.Time       `pg:"next_attempt_at"`
+	DeliveredAt   *time.Time      `pg:"delivered_at"`
+	CreatedAt     time.Time       `pg:"created_at"`
+}
+
+// OutboxMail là nội dung một email chờ gửi, Topic của message là template id
+type OutboxMail struct {
+	Tos     []string       `json:"tos"`
+	Data    map[string]any `json:"data"`
+	Message string         `json:"message"`
+}
+
+func NewOutboxMail(templateID string, mail OutboxMail) (OutboxMessage, error) {
+	return newOutboxMessage(OutboxKindMail, templateID, mail)
+}
+
+func NewOutboxEvent(event UserEvent) (OutboxMessage, error) {
+	return newOutboxMessage(OutboxKindEvent, string(event.Type), event)
+}
+
+func newOutboxMessage(kind OutboxKind, topic string, payload any) (OutboxMessage, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	return OutboxMessage{
+		ID:      uuid.NewString(),
+		Kind:    kind,
+		Topic:   topic,
+		Payload: data,
+		Status:  OutboxPending,
+	}, nil
+}
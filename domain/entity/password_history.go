@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type PasswordHistory struct {
+	tableName struct{}  `pg:"password_history,alias:ph"`
+	ID        int64     `pg:"id,pk"`
+	UserID    string    `pg:"user_id"`
+	Password  string    `pg:"password"`
+	CreatedAt time.Time `pg:"created_at"`
+}
+
+func (p *PasswordHistory) NameTable() any {
+	return p.tableName
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// ScopeAll cho token dùng mọi quyền của người dùng
+const ScopeAll = "*"
+
+// PersonalAccessToken chỉ lưu hash của token, Prefix giúp người dùng nhận ra token khi liệt kê
+type PersonalAccessToken struct {
+	tableName  struct{}   `pg:"personal_access_tokens,alias:pat"`
+	ID         string     `pg:"id,pk"`
+	UserID     string     `pg:"user_id"`
+	Name       string     `pg:"name"`
+	TokenHash  string     `pg:"token_hash,unique"`
+	Prefix     string     `pg:"prefix"`
+	Scopes     []string   `pg:"scopes,array"`
+	ExpiresAt  *time.Time `pg:"expires_at"`
+	LastUsedAt *time.Time `pg:"last_used_at"`
+	RevokedAt  *time.Time `pg:"revoked_at"`
+	CreatedAt  time.Time  `pg:"created_at"`
+	UpdatedAt  *time.Time `pg:"updated_at"`
+}
+
+func (t *PersonalAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// Allows kiểm tra scope dạng resource.action, resource.* hoặc *
+func (t *PersonalAccessToken) Allows(resource, action string) bool {
+	return slices.Contains(t.Scopes, ScopeAll) ||
+		slices.Contains(t.Scopes, resource+".*") ||
+		slices.Contains(t.Scopes, resource+"."+action)
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// ServiceAccountEmailDomain là domain của email tổng hợp cho service account
+const ServiceAccountEmailDomain = "serviceaccount.invalid"
+
+// UserContext của service account luôn có scope {Resource: PrincipalScopeResource, Action: PrincipalServiceAccount},
+// service khác dùng scope này để phân biệt service account với người dùng thay vì dựa vào email.
+// ResourceData của scope chứa ServiceAccountScopeID và ServiceAccountScopeOrganizationID (nếu thuộc tổ chức).
+const (
+	PrincipalScopeResource            = "principal"
+	PrincipalServiceAccount           = "service_account"
+	ServiceAccountScopeID             = "service_account_id"
+	ServiceAccountScopeOrganizationID = "organization_id"
+)
+
+type ServiceAccountKeyType string
+
+const (
+	ServiceAccountKeySecret    ServiceAccountKeyType = "secret"
+	ServiceAccountKeyPublicKey ServiceAccountKeyType = "public_key"
+)
+
+// ServiceAccount thuộc về đúng một người dùng hoặc một tổ chức
+type ServiceAccount struct {
+	tableName           struct{}   `pg:"service_accounts,alias:sa"`
+	ID                  string     `pg:"id,pk"`
+	Name                string     `pg:"name"`
+	Description         string     `pg:"description"`
+	OwnerUserID         string     `pg:"owner_user_id"`
+	OwnerOrganizationID string     `pg:"owner_organization_id"`
+	CreatedBy           string     `pg:"created_by"`
+	DisabledAt          *time.Time `pg:"disabled_at"`
+	CreatedAt           time.Time  `pg:"created_at"`
+	UpdatedAt           *time.Time `pg:"updated_at"`
+}
+
+func (s *ServiceAccount) Email() string {
+	return s.ID + "@" + ServiceAccountEmailDomain
+}
+
+func (s *ServiceAccount) IsDisabled() bool {
+	return s.DisabledAt != nil
+}
+
+type ServiceAccountKey struct {
+	tableName        struct{}              `pg:"service_account_keys,alias:sak"`
+	ID               string                `pg:"id,pk"`
+	ServiceAccountID string                `pg:"service_account_id"`
+	Type             ServiceAccountKeyType `pg:"type"`
+	SecretHash       string                `pg:"secret_hash"`
+	PublicKey        string                `pg:"public_key"`
+	ExpiresAt        *time.Time            `pg:"expires_at"`
+	LastUsedAt       *time.Time            `pg:"last_used_at"`
+	RevokedAt        *time.Time            `pg:"revoked_at"`
+	CreatedAt        time.Time             `pg:"created_at"`
+	UpdatedAt        *time.Time            `pg:"updated_at"`
+}
+
+func (k *ServiceAccountKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
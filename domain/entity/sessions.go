@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"net"
 	"time"
 )
 
@@ -20,10 +21,47 @@ type Session struct {
 	User      *User       `pg:"rel:has-one"`
 	Type      SessionType `pg:"type"`
 	Os        string      `pg:"os"`
+	IP        string      `pg:"ip"`
+	UserAgent string      `pg:"user_agent"`
 	ExpiredAt time.Time   `pg:"expired_at"`
 	CreatedAt time.Time   `pg:"created_at"`
 }
 
+type Device struct {
+	Os        string
+	IP        string
+	UserAgent string
+}
+
+// IPPrefix trả về mạng /24 (IPv4) hoặc /48 (IPv6) để IP động trong cùng nhà mạng
+// vẫn được xem là cùng một thiết bị.
+func (d Device) IPPrefix() string {
+	return ipPrefix(d.IP)
+}
+
+func (d Device) Match(s Session) bool {
+	return d.Os == s.Os && d.UserAgent == s.UserAgent && d.IPPrefix() == ipPrefix(s.IP)
+}
+
+func ipPrefix(raw string) string {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return raw
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
+
+func (s *Session) Device() Device {
+	return Device{
+		Os:        s.Os,
+		IP:        s.IP,
+		UserAgent: s.UserAgent,
+	}
+}
+
 func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiredAt)
 }
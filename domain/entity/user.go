@@ -6,23 +6,34 @@ import (
 	"github.com/anhvanhoa/service-core/common"
 )
 
+const (
+	UserStatusActive   common.Status = "active"
+	UserStatusInactive common.Status = "inactive"
+)
+
 type User struct {
-	tableName  struct{}      `pg:"users,alias:u"`
-	ID         string        `pg:"id,pk"`
-	Email      string        `pg:"email,unique"`
-	Phone      string        `pg:"phone,unique"`
-	Password   string        `pg:"password"`
-	FullName   string        `pg:"full_name"`
-	Avatar     string        `pg:"avatar"`
-	Bio        string        `pg:"bio"`
-	Address    string        `pg:"address"`
-	CodeVerify string        `pg:"code_verify"`
-	Veryfied   *time.Time    `pg:"veryfied"`
-	CreatedBy  string        `pg:"created_by"`
-	Status     common.Status `pg:"status"`
-	Birthday   *time.Time    `pg:"birthday"`
-	CreatedAt  time.Time     `pg:"created_at"`
-	UpdatedAt  *time.Time    `pg:"updated_at"`
+	tableName             struct{}      `pg:"users,alias:u"`
+	ID                    string        `pg:"id,pk"`
+	Email                 string        `pg:"email,unique"`
+	Phone                 string        `pg:"phone,unique"`
+	Password              string        `pg:"password"`
+	FullName              string        `pg:"full_name"`
+	Avatar                string        `pg:"avatar"`
+	Bio                   string        `pg:"bio"`
+	Address               string        `pg:"address"`
+	CodeVerify            string        `pg:"code_verify"`
+	Veryfied              *time.Time    `pg:"veryfied"`
+	CreatedBy             string        `pg:"created_by"`
+	Status                common.Status `pg:"status"`
+	Birthday              *time.Time    `pg:"birthday"`
+	PasswordChangedAt     *time.Time    `pg:"password_changed_at"`
+	PasswordResetRequired bool          `pg:"password_reset_required"`
+	PasswordExpires       bool          `pg:"password_expires"`
+	RegisteredAt          *time.Time    `pg:"registered_at"`
+	VerifyReminderSentAt  *time.Time    `pg:"verify_reminder_sent_at"`
+	ActiveOrganizationID  *string       `pg:"active_organization_id"`
+	CreatedAt             time.Time     `pg:"created_at"`
+	UpdatedAt             *time.Time    `pg:"updated_at"`
 }
 
 type UserInfor struct {
@@ -44,6 +55,21 @@ func (u *User) GetNameTable() any {
 	return u.tableName
 }
 
+func (u *User) IsSuspended() bool {
+	return u.Status == UserStatusInactive
+}
+
+// IsPasswordExpired đúng khi admin bắt đổi mật khẩu, hoặc khi tài khoản bật PasswordExpires và mật khẩu quá maxAge
+func (u *User) IsPasswordExpired(maxAge time.Duration) bool {
+	if u.PasswordResetRequired {
+		return true
+	}
+	if !u.PasswordExpires || maxAge <= 0 || u.PasswordChangedAt == nil {
+		return false
+	}
+	return time.Now().After(u.PasswordChangedAt.Add(maxAge))
+}
+
 func (u *User) GetInfor() UserInfor {
 	return UserInfor{
 		ID:       u.ID,
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserEventType string
+
+const (
+	UserEventRegistered      UserEventType = "user.registered"
+	UserEventVerified        UserEventType = "user.verified"
+	UserEventPasswordChanged UserEventType = "user.password_changed"
+	UserEventSuspended       UserEventType = "user.suspended"
+	UserEventDeleted         UserEventType = "user.deleted"
+)
+
+// UserEventVersion là phiên bản schema hiện tại, xem schemas/events
+const UserEventVersion = 1
+
+// UserEvent là envelope chung cho mọi sự kiện vòng đời người dùng.
+// ID dùng làm khóa idempotency: bên nhận có thể nhận lại cùng một sự kiện nhiều lần.
+type UserEvent struct {
+	ID         string        `json:"id"`
+	Type       UserEventType `json:"type"`
+	Version    int           `json:"version"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	UserID     string        `json:"user_id"`
+	Data       any           `json:"data,omitempty"`
+}
+
+type UserRegisteredData struct {
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+}
+
+type UserVerifiedData struct {
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+type UserPasswordChangedData struct {
+	Method string `json:"method"`
+}
+
+type UserSuspendedData struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type UserDeletedData struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+func NewUserEvent(eventType UserEventType, userID string, data any) UserEvent {
+	return UserEvent{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		Version:    UserEventVersion,
+		OccurredAt: time.Now().UTC(),
+		UserID:     userID,
+		Data:       data,
+	}
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsPasswordExpired(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	fresh := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name string
+		user User
+		want bool
+	}{
+		{name: "expiry not enabled", user: User{PasswordChangedAt: &old}, want: false},
+		{name: "enabled and too old", user: User{PasswordExpires: true, PasswordChangedAt: &old}, want: true},
+		{name: "enabled and fresh", user: User{PasswordExpires: true, PasswordChangedAt: &fresh}, want: false},
+		{name: "enabled without changed time", user: User{PasswordExpires: true}, want: false},
+		{name: "forced reset without expiry", user: User{PasswordResetRequired: true, PasswordChangedAt: &fresh}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.IsPasswordExpired(24 * time.Hour); got != tt.want {
+				t.Fatalf("IsPasswordExpired = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	u := User{PasswordExpires: true, PasswordChangedAt: &old}
+	if u.IsPasswordExpired(0) {
+		t.Fatal("maxAge 0 must disable expiry")
+	}
+}
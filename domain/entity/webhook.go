@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+type WebhookSubscription struct {
+	tableName  struct{}   `pg:"webhook_subscriptions,alias:ws"`
+	ID         string     `pg:"id,pk"`
+	URL        string     `pg:"url"`
+	Secret     string     `pg:"secret"`
+	EventTypes []string   `pg:"event_types,array"`
+	Active     bool       `pg:"active"`
+	CreatedAt  time.Time  `pg:"created_at"`
+	UpdatedAt  *time.Time `pg:"updated_at"`
+}
+
+// Accepts cho biết subscription có nhận loại sự kiện này không, "*" nhận tất cả
+func (s *WebhookSubscription) Accepts(eventType UserEventType) bool {
+	return slices.Contains(s.EventTypes, "*") || slices.Contains(s.EventTypes, string(eventType))
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "pending"
+	WebhookDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookFailed là trạng thái dead-letter, chỉ gửi lại khi replay
+	WebhookFailed WebhookDeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	tableName      struct{}              `pg:"webhook_deliveries,alias:wd"`
+	ID             string                `pg:"id,pk"`
+	SubscriptionID string                `pg:"subscription_id"`
+	EventID        string                `pg:"event_id"`
+	EventType      UserEventType         `pg:"event_type"`
+	Payload        json.RawMessage       `pg:"payload,type:jsonb"`
+	Status         WebhookDeliveryStatus `pg:"status"`
+	Attempts       int                   `pg:"attempts"`
+	ResponseStatus int                   `pg:"response_status"`
+	LastError      string                `pg:"last_error"`
+	NextAttemptAt  time.Time             `pg:"next_attempt_at"`
+	DeliveredAt    *time.Time            `pg:"delivered_at"`
+	CreatedAt      time.Time             `pg:"created_at"`
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/token"
+)
+
+// AccessTokenIssuer ký access token kèm tổ chức đang hoạt động của người dùng
+type AccessTokenIssuer interface {
+	token.TokenAuthorizeI
+	// GenAuthorizeTokenWithOrganization với organizationID rỗng cho token giống GenAuthorizeToken
+	GenAuthorizeTokenWithOrganization(id, fullName, email, organizationID string, exp time.Time) (string, error)
+	// GenServiceAccountToken ký access token cho service account, token mang thêm claim đánh dấu service account
+	GenServiceAccountToken(id, name, email, organizationID string, exp time.Time) (string, error)
+	// ServiceAccountOf trả về id service account và thời điểm hết hạn nếu token hợp lệ và là của service account
+	ServiceAccountOf(accessToken string) (string, time.Time, bool)
+}
+
+// ClientAssertionVerifier xác thực assertion do service account ký bằng private key của khóa đã đăng ký
+type ClientAssertionVerifier interface {
+	ValidatePublicKey(publicKeyPEM string) error
+	// KeyID đọc kid trong header của assertion (chưa xác thực chữ ký) để tìm khóa
+	KeyID(assertion string) (string, error)
+	// Verify trả về jti và exp của assertion để chống dùng lại
+	Verify(assertion, keyID, publicKeyPEM string) (string, time.Time, error)
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// AdvisoryLocker cho phép chỉ một replica chạy tác vụ tại một thời điểm
+type AdvisoryLocker interface {
+	// TryWithLock chạy fn nếu lấy được khóa, trả về false khi replica khác đang giữ khóa
+	TryWithLock(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error)
+}
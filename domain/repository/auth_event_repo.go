@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+	"time"
+)
+
+type AuthEventFilter struct {
+	UserID   string
+	Type     entity.AuthEventType
+	Outcome  entity.AuthEventOutcome
+	IP       string
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	PageSize int
+}
+
+type AuthEventRepository interface {
+	CreateAuthEvent(ctx context.Context, data entity.AuthEvent) error
+	ListAuthEvents(ctx context.Context, filter AuthEventFilter) ([]entity.AuthEvent, int, error)
+	DeleteAuthEventsBefore(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,5 @@
+package repository
+
+type BreachedPasswordRepository interface {
+	IsBreached(password string) bool
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event entity.UserEvent) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+)
+
+// FailedSessionStore lưu các session ghi xuống DB lỗi để job đối soát ghi lại.
+// Mỗi session được thêm/xóa riêng nên nhiều replica ghi đồng thời không làm mất session của nhau.
+type FailedSessionStore interface {
+	Add(ctx context.Context, session entity.Session) error
+	List(ctx context.Context) ([]entity.Session, error)
+	Remove(ctx context.Context, session entity.Session) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+)
+
+type InvitationRepository interface {
+	CreateInvitation(ctx context.Context, data entity.Invitation) error
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (entity.Invitation, error)
+	// RevokePendingByEmail xóa các lời mời chưa được chấp nhận của email, dùng khi mời lại
+	RevokePendingByEmail(ctx context.Context, email string) error
+	// MarkAccepted trả về false nếu lời mời đã được chấp nhận trước đó
+	MarkAccepted(ctx context.Context, id string) (bool, error)
+	// DeleteExpiredInvitees xóa tài khoản tạo sẵn của lời mời đã hết hạn mà chưa được chấp nhận, lời mời bị xóa theo khóa ngoại
+	DeleteExpiredInvitees(ctx context.Context, limit int) (int, error)
+	DeleteExpired(ctx context.Context, limit int) (int, error)
+	Tx(ctx context.Context) InvitationRepository
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+)
+
+type MailSender interface {
+	// Enqueue dùng id làm task id để enqueue lại cùng id không tạo task trùng, trả về task id
+	Enqueue(ctx context.Context, id, templateID string, mail entity.OutboxMail) (string, error)
+	// CreateHistory phải idempotent theo taskID vì relay có thể gọi lại sau khi lỗi
+	CreateHistory(ctx context.Context, taskID, templateID string, mail entity.OutboxMail) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// NonceStore ghi nhận giá trị chỉ được dùng một lần (jti của client assertion...).
+// Claim phải nguyên tử để hai request dùng cùng giá trị đồng thời chỉ một request thành công.
+type NonceStore interface {
+	// Claim trả về false nếu giá trị đã được dùng và còn trong thời hạn ttl
+	Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
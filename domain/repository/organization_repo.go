@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+	"errors"
+)
+
+// CreateOrganization trả về các lỗi dưới đây khi vi phạm ràng buộc unique, kể cả khi hai request tạo đồng thời
+var (
+	ErrOrganizationNameTaken = errors.New("organization name key already exists")
+	ErrOrganizationSlugTaken = errors.New("organization slug already exists")
+)
+
+type OrganizationRepository interface {
+	CreateOrganization(ctx context.Context, data entity.Organization) error
+	GetOrganizationByID(ctx context.Context, id string) (entity.Organization, error)
+	SlugExists(ctx context.Context, slug string) (bool, error)
+	// NameExists so sánh không phân biệt hoa thường, createdBy rỗng thì kiểm tra trên toàn hệ thống
+	NameExists(ctx context.Context, name, createdBy string) (bool, error)
+	AddMember(ctx context.Context, data entity.OrganizationMember) error
+	GetMember(ctx context.Context, organizationID, userID string) (entity.OrganizationMember, error)
+	ListMembershipsByUser(ctx context.Context, userID string) ([]entity.OrganizationMember, error)
+	Tx(ctx context.Context) OrganizationRepository
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+	"time"
+)
+
+type OutboxRepository interface {
+	CreateOutboxMessage(ctx context.Context, data entity.OutboxMessage) error
+	// ClaimDue lấy các message đến hạn và đẩy next_attempt_at thêm lease để replica khác không lấy trùng
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error)
+	SetTaskID(ctx context.Context, id, taskID string) error
+	MarkDelivered(ctx context.Context, id string) error
+	MarkRetry(ctx context.Context, id string, attempts int, next time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string) error
+	DeleteDeliveredBefore(ctx context.Context, before time.Time) (int, error)
+	Tx(ctx context.Context) OutboxRepository
+}
@@ -0,0 +1,7 @@
+package repository
+
+type PasswordHasher interface {
+	HashPassword(password string) (string, error)
+	VerifyPassword(hash, password string) (bool, error)
+	NeedsRehash(hash string) bool
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+)
+
+type PasswordHistoryRepository interface {
+	CreatePasswordHistory(ctx context.Context, data entity.PasswordHistory) error
+	GetRecentByUserID(userID string, limit int) ([]entity.PasswordHistory, error)
+	PruneByUserID(ctx context.Context, userID string, keep int) error
+	Tx(ctx context.Context) PasswordHistoryRepository
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+)
+
+type PersonalAccessTokenRepository interface {
+	CreateToken(ctx context.Context, data entity.PersonalAccessToken) error
+	GetTokenByHash(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error)
+	ListTokensByUser(ctx context.Context, userID string) ([]entity.PersonalAccessToken, error)
+	NameExists(ctx context.Context, userID, name string) (bool, error)
+	// RevokeToken trả về token đã thu hồi, ok false nếu token không thuộc user hoặc đã bị thu hồi
+	RevokeToken(ctx context.Context, userID, id string) (entity.PersonalAccessToken, bool, error)
+	TouchLastUsed(ctx context.Context, id string) error
+}
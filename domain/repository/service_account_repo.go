@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+)
+
+type ServiceAccountFilter struct {
+	OwnerUserID         string
+	OwnerOrganizationID string
+}
+
+type ServiceAccountRepository interface {
+	CreateServiceAccount(ctx context.Context, data entity.ServiceAccount) error
+	GetServiceAccountByID(ctx context.Context, id string) (entity.ServiceAccount, error)
+	ListServiceAccounts(ctx context.Context, filter ServiceAccountFilter) ([]entity.ServiceAccount, error)
+	SetDisabled(ctx context.Context, id string, disabled bool) error
+	CreateKey(ctx context.Context, data entity.ServiceAccountKey) error
+	GetKeyByID(ctx context.Context, id string) (entity.ServiceAccountKey, error)
+	ListKeys(ctx context.Context, serviceAccountID string) ([]entity.ServiceAccountKey, error)
+	// RevokeKey trả về false nếu khóa không thuộc service account hoặc đã bị thu hồi
+	RevokeKey(ctx context.Context, serviceAccountID, keyID string) (bool, error)
+	TouchKeyLastUsed(ctx context.Context, id string) error
+}
@@ -7,9 +7,12 @@ import (
 
 type SessionRepository interface {
 	CreateSession(data entity.Session) error
+	// CreateSessionAsync dùng khi session đã nằm trong Redis, việc ghi xuống DB có thể diễn ra sau khi hàm trả về
+	CreateSessionAsync(data entity.Session) error
 	GetSessionAliveByToken(typeSession entity.SessionType, token string) (entity.Session, error)
 	GetSessionAliveByTokenAndIdUser(typeSession entity.SessionType, token, idUser string) (entity.Session, error)
 	GetSessionForgotAliveByTokenAndIdUser(token, idUser string) (entity.Session, error)
+	GetSessionsByUserID(sessionType entity.SessionType, userID string) ([]entity.Session, error)
 	TokenExists(token string) bool
 	DeleteSessionByTypeAndUserID(ctx context.Context, sessionType entity.SessionType, userID string) error
 	DeleteSessionByTypeAndToken(ctx context.Context, sessionType entity.SessionType, token string) error
@@ -17,7 +20,7 @@ type SessionRepository interface {
 	DeleteSessionAuthByToken(ctx context.Context, token string) error
 	DeleteSessionVerifyByToken(ctx context.Context, token string) error
 	DeleteSessionForgotByToken(ctx context.Context, token string) error
-	DeleteAllSessionsExpired(ctx context.Context) error
+	DeleteExpiredSessions(ctx context.Context, limit int) (int, error)
 	DeleteAllSessionsForgot(ctx context.Context) error
 	DeleteSessionForgotByTokenAndIdUser(ctx context.Context, token, idUser string) error
 	Tx(ctx context.Context) SessionRepository
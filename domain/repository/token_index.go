@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// TokenIndex lưu chỉ mục user -> các token đang cache để xử lý mọi token của user cùng lúc.
+// Mỗi token được thêm riêng nên nhiều replica ghi đồng thời không làm mất token của nhau.
+type TokenIndex interface {
+	Add(ctx context.Context, userID, token string, exp time.Time) error
+	// Take trả về các token chưa hết hạn của user kèm thời điểm hết hạn và xóa chỉ mục trong cùng một bước,
+	// token thêm trong lúc xử lý nằm ở chỉ mục mới nên không bị mất
+	Take(ctx context.Context, userID string) (map[string]time.Time, error)
+}
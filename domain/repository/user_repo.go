@@ -3,8 +3,17 @@ package repository
 import (
 	"auth-service/domain/entity"
 	"context"
+	"time"
+
+	"github.com/anhvanhoa/service-core/common"
 )
 
+// UnverifiedFilter chọn tài khoản chưa xác thực, RemindedBefore khác nil thì chỉ lấy tài khoản đã được nhắc trước thời điểm đó
+type UnverifiedFilter struct {
+	RegisteredBefore time.Time
+	RemindedBefore   *time.Time
+}
+
 type UserRepository interface {
 	CreateUser(entity.User) (entity.UserInfor, error)
 	GetUserByEmailOrPhone(val string) (entity.User, error)
@@ -14,6 +23,15 @@ type UserRepository interface {
 	CheckUserVerified(email string) (bool, error)
 	UpdateUser(Id string, data entity.User) (entity.UserInfor, error)
 	UpdateUserByEmail(email string, data entity.User) (bool, error)
+	UpdatePasswordHash(id, oldHash, newHash string) (bool, error)
+	UpdatePassword(id, hash string) error
+	RequirePasswordReset(ctx context.Context, ids []string) (int, error)
+	SetPasswordExpires(ctx context.Context, ids []string, expires bool) (int, error)
+	UpdateStatus(ctx context.Context, ids []string, status common.Status) ([]string, error)
 	DeleteByID(ctx context.Context, id string) error
+	ListUnverifiedToRemind(ctx context.Context, registeredBefore time.Time, limit int) ([]entity.User, error)
+	MarkVerifyReminderSent(ctx context.Context, id string) error
+	DeleteUnverified(ctx context.Context, filter UnverifiedFilter, limit int) (int, error)
+	SetActiveOrganization(ctx context.Context, id, organizationID string) error
 	Tx(ctx context.Context) UserRepository
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"auth-service/domain/entity"
+	"context"
+	"time"
+)
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	Status         entity.WebhookDeliveryStatus
+	Page           int
+	PageSize       int
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, data entity.WebhookSubscription) error
+	GetSubscriptionByID(ctx context.Context, id string) (entity.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	GetActiveSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	// CreateDeliveries bỏ qua delivery đã tồn tại cho cùng subscription và event
+	CreateDeliveries(ctx context.Context, data []entity.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id string, attempts, responseStatus int) error
+	MarkRetry(ctx context.Context, id string, attempts, responseStatus int, next time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id string, attempts, responseStatus int, lastError string) error
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]entity.WebhookDelivery, int, error)
+	ReplayDelivery(ctx context.Context, id string) (bool, error)
+}
+
+type WebhookSender interface {
+	// Send trả về mã HTTP nhận được (0 nếu không kết nối được)
+	Send(ctx context.Context, sub entity.WebhookSubscription, delivery entity.WebhookDelivery) (int, error)
+}
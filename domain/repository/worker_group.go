@@ -0,0 +1,8 @@
+package repository
+
+import "context"
+
+// WorkerGroup chạy các thao tác ghi bất đồng bộ; khi service tắt sẽ chờ các thao tác đã nhận hoàn tất
+type WorkerGroup interface {
+	Go(name string, fn func(ctx context.Context) error)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"time"
+)
+
+const (
+	defaultAuthEventPageSize = 20
+	maxAuthEventPageSize     = 100
+)
+
+type AuthEventUsecase interface {
+	Record(ctx context.Context, event entity.AuthEvent) error
+	ListByUser(ctx context.Context, userID string, page, pageSize int) ([]entity.AuthEvent, int, error)
+	List(ctx context.Context, filter repository.AuthEventFilter) ([]entity.AuthEvent, int, error)
+	DeleteExpired(ctx context.Context, retention time.Duration) (int, error)
+}
+
+type authEventUsecaseImpl struct {
+	authEventRepo repository.AuthEventRepository
+}
+
+func NewAuthEventUsecase(authEventRepo repository.AuthEventRepository) AuthEventUsecase {
+	return &authEventUsecaseImpl{
+		authEventRepo: authEventRepo,
+	}
+}
+
+func (uc *authEventUsecaseImpl) Record(ctx context.Context, event entity.AuthEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	return uc.authEventRepo.CreateAuthEvent(ctx, event)
+}
+
+func (uc *authEventUsecaseImpl) ListByUser(ctx context.Context, userID string, page, pageSize int) ([]entity.AuthEvent, int, error) {
+	if userID == "" {
+		return nil, 0, ErrUserNotFound
+	}
+	return uc.List(ctx, repository.AuthEventFilter{
+		UserID:   userID,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+func (uc *authEventUsecaseImpl) List(ctx context.Context, filter repository.AuthEventFilter) ([]entity.AuthEvent, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultAuthEventPageSize
+	}
+	if filter.PageSize > maxAuthEventPageSize {
+		filter.PageSize = maxAuthEventPageSize
+	}
+	return uc.authEventRepo.ListAuthEvents(ctx, filter)
+}
+
+func (uc *authEventUsecaseImpl) DeleteExpired(ctx context.Context, retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+	return uc.authEventRepo.DeleteAuthEventsBefore(ctx, time.Now().Add(-retention))
+}
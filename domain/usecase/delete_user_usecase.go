@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+)
+
+type DeleteUserUsecase interface {
+	Execute(ctx context.Context, userIDs []string, reason string) (int, error)
+}
+
+type deleteUserUsecaseImpl struct {
+	userRepo   repository.UserRepository
+	tx         repository.ManagerTransaction
+	publisher  repository.EventPublisher
+	revocation TokenRevocationUsecase
+}
+
+func NewDeleteUserUsecase(
+	userRepo repository.UserRepository,
+	tx repository.ManagerTransaction,
+	publisher repository.EventPublisher,
+	revocation TokenRevocationUsecase,
+) DeleteUserUsecase {
+	return &deleteUserUsecaseImpl{
+		userRepo:   userRepo,
+		tx:         tx,
+		publisher:  publisher,
+		revocation: revocation,
+	}
+}
+
+// Execute xóa người dùng, session và lịch sử mật khẩu bị xóa theo ON DELETE CASCADE
+func (uc *deleteUserUsecaseImpl) Execute(ctx context.Context, userIDs []string, reason string) (int, error) {
+	var deleted []string
+	err := uc.tx.RunInTransaction(func(ctx context.Context) error {
+		for _, id := range userIDs {
+			if _, err := uc.userRepo.GetUserByID(id); err != nil {
+				continue
+			}
+			if err := uc.userRepo.Tx(ctx).DeleteByID(ctx, id); err != nil {
+				return err
+			}
+			if err := uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventDeleted, id, entity.UserDeletedData{
+				Reason: reason,
+			})); err != nil {
+				return err
+			}
+			deleted = append(deleted, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(deleted), uc.revocation.RevokeUsers(ctx, deleted...)
+}
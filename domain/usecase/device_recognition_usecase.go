@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+
+	"github.com/anhvanhoa/service-core/domain/queue"
+)
+
+type DeviceRecognitionUsecase interface {
+	IsKnownDevice(userID string, device entity.Device) (bool, error)
+	SendMail(payload queue.PayloadI) (string, error)
+}
+
+type deviceRecognitionUsecaseImpl struct {
+	sessionRepo repository.SessionRepository
+	qc          queue.QueueClient
+}
+
+func NewDeviceRecognitionUsecase(sessionRepo repository.SessionRepository, qc queue.QueueClient) DeviceRecognitionUsecase {
+	return &deviceRecognitionUsecaseImpl{
+		sessionRepo: sessionRepo,
+		qc:          qc,
+	}
+}
+
+// IsKnownDevice so khớp OS, user agent và dải IP với các phiên đăng nhập trước đó.
+// Lần đăng nhập đầu tiên luôn được xem là thiết bị đã biết.
+func (uc *deviceRecognitionUsecaseImpl) IsKnownDevice(userID string, device entity.Device) (bool, error) {
+	sessions, err := uc.sessionRepo.GetSessionsByUserID(entity.SessionTypeAuth, userID)
+	if err != nil {
+		return true, err
+	}
+	if len(sessions) == 0 {
+		return true, nil
+	}
+	for _, s := range sessions {
+		if device.Match(s) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (uc *deviceRecognitionUsecaseImpl) SendMail(payload queue.PayloadI) (string, error) {
+	return uc.qc.EnqueueAnyTask(payload)
+}
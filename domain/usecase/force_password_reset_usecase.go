@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+)
+
+type ForcePasswordResetUsecase interface {
+	Execute(ctx context.Context, userIDs []string) (int, error)
+}
+
+type forcePasswordResetUsecaseImpl struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	tx          repository.ManagerTransaction
+	revocation  TokenRevocationUsecase
+}
+
+func NewForcePasswordResetUsecase(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	tx repository.ManagerTransaction,
+	revocation TokenRevocationUsecase,
+) ForcePasswordResetUsecase {
+	return &forcePasswordResetUsecaseImpl{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		tx:          tx,
+		revocation:  revocation,
+	}
+}
+
+func (uc *forcePasswordResetUsecaseImpl) Execute(ctx context.Context, userIDs []string) (int, error) {
+	var affected int
+	err := uc.tx.RunInTransaction(func(ctx context.Context) error {
+		var err error
+		if affected, err = uc.userRepo.Tx(ctx).RequirePasswordReset(ctx, userIDs); err != nil {
+			return err
+		}
+		for _, id := range userIDs {
+			if err := uc.sessionRepo.Tx(ctx).DeleteSessionByTypeAndUserID(ctx, entity.SessionTypeAuth, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, uc.revocation.RevokeUsers(ctx, userIDs...)
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/anhvanhoa/service-core/domain/cache"
 	"github.com/anhvanhoa/service-core/domain/log"
 	"github.com/anhvanhoa/service-core/domain/oops"
-	"github.com/anhvanhoa/service-core/domain/queue"
 	"github.com/anhvanhoa/service-core/domain/saga"
 	"github.com/anhvanhoa/service-core/domain/token"
 )
@@ -47,11 +46,10 @@ type CompensateForgotPassword struct {
 
 type ForgotPasswordUsecase interface {
 	ForgotPassword(email, os string, method ForgotPasswordType) (ForgotPasswordRes, error)
+	RequestResetPassword(email, os string, method ForgotPasswordType, resetLink string) (ForgotPasswordRes, error)
 	saveCodeOrToken(typeForgot ForgotPasswordType, userID, codeOrToken, os string, exp time.Time) error
-	SendEmailForgotPassword(payload queue.PayloadI) (string, error)
-	CompensateSendEmail(ctx context.Context, taskID string) error
 	generateRandomCode(length int) string
-	ForgotPasswordWithSaga(sagaID string, execute common.ExecuteSaga) error
+	ForgotPasswordWithSaga(ctx context.Context, sagaID string, execute common.ExecuteSaga) error
 	CompensateForgotPassword(ctx context.Context, data CompensateForgotPassword) error
 }
 
@@ -61,9 +59,10 @@ type forgotPasswordUsecaseImpl struct {
 	tx          repository.ManagerTransaction
 	token       token.TokenForgotPasswordI
 	cache       cache.CacheI
-	qc          queue.QueueClient
+	outboxRepo  repository.OutboxRepository
 	saga        saga.SagaManager
 	log         *log.LogGRPCImpl
+	workers     repository.WorkerGroup
 }
 
 func NewForgotPasswordUsecase(
@@ -72,9 +71,10 @@ func NewForgotPasswordUsecase(
 	tx repository.ManagerTransaction,
 	token token.TokenForgotPasswordI,
 	cache cache.CacheI,
-	qc queue.QueueClient,
+	outboxRepo repository.OutboxRepository,
 	saga saga.SagaManager,
 	log *log.LogGRPCImpl,
+	workers repository.WorkerGroup,
 ) ForgotPasswordUsecase {
 	return &forgotPasswordUsecaseImpl{
 		userRepo,
@@ -82,9 +82,10 @@ func NewForgotPasswordUsecase(
 		tx,
 		token,
 		cache,
-		qc,
+		outboxRepo,
 		saga,
 		log,
+		workers,
 	}
 }
 
@@ -97,23 +98,22 @@ func (uc *forgotPasswordUsecaseImpl) saveCodeOrToken(typeForgot ForgotPasswordTy
 		ExpiredAt: exp,
 		CreatedAt: time.Now(),
 	}
-	key := codeOrToken
-	if typeForgot == ForgotByCode && len(codeOrToken) == 6 {
-		key = fmt.Sprintf("%s:%s", codeOrToken, userID)
-	}
+	key := forgotCacheKey(typeForgot, userID, codeOrToken)
 	if err := uc.cache.Set(key, []byte(codeOrToken), constants.ForgotExpiredAt*time.Minute); err != nil {
 		if err := uc.sessionRepo.CreateSession(session); err != nil {
 			return ErrCreateSession
 		}
-	} else {
-		go uc.sessionRepo.CreateSession(session)
+	} else if err := uc.sessionRepo.CreateSessionAsync(session); err != nil {
+		return ErrCreateSession
 	}
 	return nil
 }
 
-func (uc *forgotPasswordUsecaseImpl) SendEmailForgotPassword(payload queue.PayloadI) (string, error) {
-	go uc.sessionRepo.DeleteAllSessionsForgot(context.Background())
-	return uc.qc.EnqueueAnyTask(payload)
+func forgotCacheKey(typeForgot ForgotPasswordType, userID, codeOrToken string) string {
+	if typeForgot == ForgotByCode && len(codeOrToken) == 6 {
+		return fmt.Sprintf("%s:%s", codeOrToken, userID)
+	}
+	return codeOrToken
 }
 
 func (uc *forgotPasswordUsecaseImpl) ForgotPassword(email, os string, method ForgotPasswordType) (ForgotPasswordRes, error) {
@@ -145,6 +145,61 @@ func (uc *forgotPasswordUsecaseImpl) ForgotPassword(email, os string, method For
 	return resForgotPassword, ErrValidateForgotPassword
 }
 
+// RequestResetPassword tạo phiên quên mật khẩu và email gửi mã/link trong cùng một transaction,
+// email được relay outbox gửi đi sau khi commit.
+func (uc *forgotPasswordUsecaseImpl) RequestResetPassword(email, os string, method ForgotPasswordType, resetLink string) (ForgotPasswordRes, error) {
+	var res ForgotPasswordRes
+	user, err := uc.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return res, ErrUserNotFound
+	}
+	res.User = user.GetInfor()
+	exp := time.Now().Add(constants.ForgotExpiredAt * time.Minute)
+	data := map[string]any{"user": res.User}
+	var codeOrToken string
+	switch method {
+	case ForgotByCode:
+		res.Code = uc.generateRandomCode(6)
+		codeOrToken = res.Code
+		data["code"] = res.Code
+	case ForgotByToken:
+		if res.Token, err = uc.token.GenForgotPasswordToken(user.ID, uc.generateRandomCode(6), exp); err != nil {
+			return res, err
+		}
+		codeOrToken = res.Token
+		data["link"] = resetLink + res.Token
+	default:
+		return res, ErrValidateForgotPassword
+	}
+
+	err = uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if err := uc.sessionRepo.Tx(ctx).CreateSession(entity.Session{
+			Token:     codeOrToken,
+			UserID:    user.ID,
+			Type:      entity.SessionTypeForgot,
+			Os:        os,
+			ExpiredAt: exp,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return ErrCreateSession
+		}
+		mail, err := entity.NewOutboxMail(constants.TPL_FORGOT_MAIL, entity.OutboxMail{
+			Tos:     []string{user.Email},
+			Data:    data,
+			Message: "Send email forgot password to " + user.Email,
+		})
+		if err != nil {
+			return err
+		}
+		return uc.outboxRepo.Tx(ctx).CreateOutboxMessage(ctx, mail)
+	})
+	if err != nil {
+		return res, err
+	}
+	uc.cache.Set(forgotCacheKey(method, user.ID, codeOrToken), []byte(codeOrToken), constants.ForgotExpiredAt*time.Minute)
+	return res, nil
+}
+
 func (uc *forgotPasswordUsecaseImpl) generateRandomCode(length int) string {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	min := int64(1)
@@ -156,19 +211,15 @@ func (uc *forgotPasswordUsecaseImpl) generateRandomCode(length int) string {
 	return strconv.FormatInt(num, 10)
 }
 
-func (uc *forgotPasswordUsecaseImpl) ForgotPasswordWithSaga(sagaID string, execute common.ExecuteSaga) error {
-	ctx := context.Background()
-	sagaTx := uc.saga.NewTransaction(sagaID, ctx)
+func (uc *forgotPasswordUsecaseImpl) ForgotPasswordWithSaga(ctx context.Context, sagaID string, execute common.ExecuteSaga) error {
+	// Giữ span/trace của request nhưng không để client hủy request làm dừng saga giữa chừng
+	sagaTx := uc.saga.NewTransaction(sagaID, context.WithoutCancel(ctx))
 	if err := execute(sagaTx.GetContext(), sagaTx); err != nil {
 		return err
 	}
 	return sagaTx.Execute(sagaTx.GetContext(), sagaID)
 }
 
-func (uc *forgotPasswordUsecaseImpl) CompensateSendEmail(ctx context.Context, taskID string) error {
-	return uc.qc.CancelTask(taskID)
-}
-
 func (uc *forgotPasswordUsecaseImpl) CompensateForgotPassword(ctx context.Context, data CompensateForgotPassword) error {
 	switch data.Type {
 	case ForgotByCode:
@@ -178,11 +229,9 @@ func (uc *forgotPasswordUsecaseImpl) CompensateForgotPassword(ctx context.Contex
 				return err
 			}
 		}
-		go func() {
-			if err := uc.sessionRepo.DeleteSessionForgotByTokenAndIdUser(ctx, data.Code, data.UserID); err != nil {
-				uc.log.Error("async delete failed: " + err.Error())
-			}
-		}()
+		uc.workers.Go("delete_forgot_session", func(ctx context.Context) error {
+			return uc.sessionRepo.DeleteSessionForgotByTokenAndIdUser(ctx, data.Code, data.UserID)
+		})
 		return nil
 
 	case ForgotByToken:
@@ -191,11 +240,9 @@ func (uc *forgotPasswordUsecaseImpl) CompensateForgotPassword(ctx context.Contex
 				return err
 			}
 		}
-		go func() {
-			if err := uc.sessionRepo.DeleteSessionForgotByToken(ctx, data.Token); err != nil {
-				uc.log.Error("async delete failed: " + err.Error())
-			}
-		}()
+		uc.workers.Go("delete_forgot_session", func(ctx context.Context) error {
+			return uc.sessionRepo.DeleteSessionForgotByToken(ctx, data.Token)
+		})
 		return nil
 
 	default:
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"auth-service/constants"
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/mail"
+	"strings"
+	"time"
+
+	hashpass "github.com/anhvanhoa/service-core/domain/hash_pass"
+	"github.com/anhvanhoa/service-core/domain/oops"
+	"github.com/google/uuid"
+)
+
+const (
+	invitationTokenLength     = 32
+	defaultInvitationLifetime = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidInvitationEmail = oops.New("Email người được mời không hợp lệ")
+	ErrInvitationEmailExists  = oops.New("Email đã có tài khoản, hãy thêm người dùng vào tổ chức thay vì mời")
+	ErrInvitationRoleNoOrg    = oops.New("Chỉ được chọn vai trò khi mời vào một tổ chức")
+	ErrInvitationInvalid      = oops.New("Lời mời không tồn tại hoặc đã hết hạn")
+	ErrInvitationAccepted     = oops.New("Tài khoản đã được kích hoạt, vui lòng đăng nhập")
+	ErrInvitationPassword     = oops.New("Mật khẩu và xác nhận mật khẩu không khớp")
+	ErrInvitationNoPassword   = oops.New("Vui lòng nhập mật khẩu")
+)
+
+type InvitationPolicy struct {
+	Lifetime   time.Duration
+	AcceptLink string
+}
+
+type InviteReq struct {
+	InviterID string
+	Email     string
+	FullName  string
+	// OrganizationID rỗng là lời mời vào hệ thống, chỉ admin được dùng (admin CLI);
+	// có tổ chức thì người mời phải là owner/admin của tổ chức đó
+	OrganizationID string
+	Role           entity.OrganizationRole
+}
+
+type AcceptInvitationReq struct {
+	Token           string
+	FullName        string
+	Password        string
+	ConfirmPassword string
+}
+
+type InvitationUsecase interface {
+	// Invite trả về token chưa băm, token chỉ được gửi qua mail và không lưu lại
+	Invite(ctx context.Context, req InviteReq) (entity.Invitation, string, error)
+	AcceptInvitation(ctx context.Context, req AcceptInvitationReq) (entity.UserInfor, error)
+}
+
+type invitationUsecaseImpl struct {
+	invitationRepo   repository.InvitationRepository
+	userRepo         repository.UserRepository
+	organizationRepo repository.OrganizationRepository
+	outboxRepo       repository.OutboxRepository
+	tx               repository.ManagerTransaction
+	hashPass         hashpass.HashPassI
+	passwordPolicy   PasswordPolicyUsecase
+	passwordHistory  PasswordHistoryUsecase
+	publisher        repository.EventPublisher
+	policy           InvitationPolicy
+}
+
+func NewInvitationUsecase(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	outboxRepo repository.OutboxRepository,
+	tx repository.ManagerTransaction,
+	hashPass hashpass.HashPassI,
+	passwordPolicy PasswordPolicyUsecase,
+	passwordHistory PasswordHistoryUsecase,
+	publisher repository.EventPublisher,
+	policy InvitationPolicy,
+) InvitationUsecase {
+	if policy.Lifetime <= 0 {
+		policy.Lifetime = defaultInvitationLifetime
+	}
+	return &invitationUsecaseImpl{
+		invitationRepo:   invitationRepo,
+		userRepo:         userRepo,
+		organizationRepo: organizationRepo,
+		outboxRepo:       outboxRepo,
+		tx:               tx,
+		hashPass:         hashPass,
+		passwordPolicy:   passwordPolicy,
+		passwordHistory:  passwordHistory,
+		publisher:        publisher,
+		policy:           policy,
+	}
+}
+
+// Invite tạo sẵn tài khoản chưa xác thực cho email (hoặc dùng lại tài khoản chưa xác thực), thu hồi lời mời cũ
+// và gửi mail mời qua outbox trong cùng transaction
+func (uc *invitationUsecaseImpl) Invite(ctx context.Context, req InviteReq) (entity.Invitation, string, error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(req.Email))
+	if err != nil {
+		return entity.Invitation{}, "", ErrInvalidInvitationEmail
+	}
+	email := strings.ToLower(addr.Address)
+	inviter, err := uc.userRepo.GetUserByID(req.InviterID)
+	if err != nil {
+		return entity.Invitation{}, "", ErrUserNotFound
+	}
+	var org entity.Organization
+	if req.OrganizationID == "" {
+		if req.Role != "" {
+			return entity.Invitation{}, "", ErrInvitationRoleNoOrg
+		}
+	} else {
+		if req.Role == "" {
+			req.Role = entity.OrganizationRoleMember
+		}
+		if !req.Role.IsValid() {
+			return entity.Invitation{}, "", ErrInvalidOrganizationRole
+		}
+		if org, err = uc.organizationRepo.GetOrganizationByID(ctx, req.OrganizationID); err != nil {
+			return entity.Invitation{}, "", ErrOrganizationNotFound
+		}
+		actor, err := uc.organizationRepo.GetMember(ctx, org.ID, inviter.ID)
+		if err != nil || !actor.Role.CanManageMembers() {
+			return entity.Invitation{}, "", ErrOrganizationForbidden
+		}
+		if req.Role == entity.OrganizationRoleOwner && actor.Role != entity.OrganizationRoleOwner {
+			return entity.Invitation{}, "", ErrOrganizationForbidden
+		}
+	}
+
+	invitee, err := uc.userRepo.GetUserByEmail(email)
+	provision := err != nil
+	if !provision && invitee.Veryfied != nil {
+		return entity.Invitation{}, "", ErrInvitationEmailExists
+	}
+	if provision {
+		invitee = entity.User{
+			ID:        uuid.NewString(),
+			Email:     email,
+			FullName:  req.FullName,
+			CreatedBy: inviter.ID,
+		}
+	}
+
+	token, tokenHash, err := newInvitationToken()
+	if err != nil {
+		return entity.Invitation{}, "", err
+	}
+	now := time.Now()
+	invitation := entity.Invitation{
+		ID:             uuid.NewString(),
+		Email:          email,
+		TokenHash:      tokenHash,
+		UserID:         invitee.ID,
+		InvitedBy:      inviter.ID,
+		OrganizationID: org.ID,
+		Role:           req.Role,
+		ExpiresAt:      now.Add(uc.policy.Lifetime),
+		CreatedAt:      now,
+	}
+	err = uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if provision {
+			if _, err := uc.userRepo.Tx(ctx).CreateUser(invitee); err != nil {
+				return err
+			}
+		}
+		if err := uc.invitationRepo.Tx(ctx).RevokePendingByEmail(ctx, email); err != nil {
+			return err
+		}
+		if err := uc.invitationRepo.Tx(ctx).CreateInvitation(ctx, invitation); err != nil {
+			return err
+		}
+		mail, err := entity.NewOutboxMail(constants.TPL_INVITATION_MAIL, entity.OutboxMail{
+			Tos: []string{email},
+			Data: map[string]any{
+				"user":         invitee.GetInfor(),
+				"inviter":      inviter.GetInfor(),
+				"organization": org.Name,
+				"role":         req.Role,
+				"link":         uc.policy.AcceptLink + token,
+				"expires_at":   invitation.ExpiresAt,
+			},
+			Message: "Send invitation to " + email,
+		})
+		if err != nil {
+			return err
+		}
+		return uc.outboxRepo.Tx(ctx).CreateOutboxMessage(ctx, mail)
+	})
+	if err != nil {
+		return entity.Invitation{}, "", err
+	}
+	return invitation, token, nil
+}
+
+// AcceptInvitation đặt mật khẩu, xác thực tài khoản và ghi nhận người mời vào CreatedBy,
+// lời mời vào tổ chức thì thêm thành viên với vai trò được gợi ý
+func (uc *invitationUsecaseImpl) AcceptInvitation(ctx context.Context, req AcceptInvitationReq) (entity.UserInfor, error) {
+	if req.Password == "" {
+		return entity.UserInfor{}, ErrInvitationNoPassword
+	}
+	if req.Password != req.ConfirmPassword {
+		return entity.UserInfor{}, ErrInvitationPassword
+	}
+	if err := uc.passwordPolicy.Validate(req.Password); err != nil {
+		return entity.UserInfor{}, err
+	}
+	invitation, err := uc.invitationRepo.GetInvitationByTokenHash(ctx, hashInvitationToken(req.Token))
+	if err != nil || !invitation.IsPending(time.Now()) {
+		return entity.UserInfor{}, ErrInvitationInvalid
+	}
+	invitee, err := uc.userRepo.GetUserByID(invitation.UserID)
+	if err != nil {
+		return entity.UserInfor{}, ErrInvitationInvalid
+	}
+	if invitee.Veryfied != nil {
+		return entity.UserInfor{}, ErrInvitationAccepted
+	}
+	hash, err := uc.hashPass.HashPassword(req.Password)
+	if err != nil {
+		return entity.UserInfor{}, err
+	}
+
+	now := time.Now()
+	update := entity.User{
+		Password:          hash,
+		FullName:          strings.TrimSpace(req.FullName),
+		Veryfied:          &now,
+		PasswordChangedAt: &now,
+		RegisteredAt:      &now,
+		CreatedBy:         invitation.InvitedBy,
+	}
+	err = uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if ok, err := uc.invitationRepo.Tx(ctx).MarkAccepted(ctx, invitation.ID); err != nil {
+			return err
+		} else if !ok {
+			return ErrInvitationInvalid
+		}
+		if _, err := uc.userRepo.Tx(ctx).UpdateUser(invitee.ID, update); err != nil {
+			return err
+		}
+		if err := uc.passwordHistory.Save(ctx, invitee.ID, hash); err != nil {
+			return err
+		}
+		if invitation.OrganizationID != "" {
+			if err := uc.joinOrganization(ctx, invitee, invitation); err != nil {
+				return err
+			}
+		}
+		if update.FullName == "" {
+			update.FullName = invitee.FullName
+		}
+		if err := uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventRegistered, invitee.ID, entity.UserRegisteredData{
+			Email:    invitee.Email,
+			FullName: update.FullName,
+		})); err != nil {
+			return err
+		}
+		return uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventVerified, invitee.ID, entity.UserVerifiedData{
+			VerifiedAt: now,
+		}))
+	})
+	if err != nil {
+		return entity.UserInfor{}, err
+	}
+	invitee.FullName = update.FullName
+	return invitee.GetInfor(), nil
+}
+
+func (uc *invitationUsecaseImpl) joinOrganization(ctx context.Context, invitee entity.User, invitation entity.Invitation) error {
+	if _, err := uc.organizationRepo.Tx(ctx).GetMember(ctx, invitation.OrganizationID, invitee.ID); err != nil {
+		if err := uc.organizationRepo.Tx(ctx).AddMember(ctx, entity.OrganizationMember{
+			OrganizationID: invitation.OrganizationID,
+			UserID:         invitee.ID,
+			Role:           invitation.Role,
+			CreatedAt:      time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+	if invitee.ActiveOrganizationID == nil {
+		return uc.userRepo.Tx(ctx).SetActiveOrganization(ctx, invitee.ID, invitation.OrganizationID)
+	}
+	return nil
+}
+
+func newInvitationToken() (string, string, error) {
+	b := make([]byte, invitationTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, hashInvitationToken(token), nil
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
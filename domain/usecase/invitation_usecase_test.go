@@ -0,0 +1,402 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errTestNotFound = errors.New("not found")
+
+// fakeTx chạy fn ngay, các repo giả không phân biệt trong/ngoài transaction
+type fakeTx struct {
+	repository.ManagerTransaction
+}
+
+func (fakeTx) RunInTransaction(fn func(ctx context.Context) error) error {
+	return fn(context.Background())
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[string]entity.User
+}
+
+func (r *fakeUserRepo) CreateUser(u entity.User) (entity.UserInfor, error) {
+	for _, v := range r.users {
+		if v.Email == u.Email {
+			return entity.UserInfor{}, errors.New("duplicate email")
+		}
+	}
+	r.users[u.ID] = u
+	return u.GetInfor(), nil
+}
+
+func (r *fakeUserRepo) GetUserByID(id string) (entity.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return entity.User{}, errTestNotFound
+	}
+	return u, nil
+}
+
+func (r *fakeUserRepo) GetUserByEmail(email string) (entity.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return entity.User{}, errTestNotFound
+}
+
+func (r *fakeUserRepo) UpdateUser(id string, data entity.User) (entity.UserInfor, error) {
+	u := r.users[id]
+	if data.Password != "" {
+		u.Password = data.Password
+	}
+	if data.FullName != "" {
+		u.FullName = data.FullName
+	}
+	if data.Veryfied != nil {
+		u.Veryfied = data.Veryfied
+	}
+	if data.CreatedBy != "" {
+		u.CreatedBy = data.CreatedBy
+	}
+	r.users[id] = u
+	return u.GetInfor(), nil
+}
+
+func (r *fakeUserRepo) SetActiveOrganization(ctx context.Context, id, organizationID string) error {
+	u := r.users[id]
+	u.ActiveOrganizationID = &organizationID
+	r.users[id] = u
+	return nil
+}
+
+func (r *fakeUserRepo) Tx(ctx context.Context) repository.UserRepository { return r }
+
+type fakeInvitationRepo struct {
+	repository.InvitationRepository
+	invitations map[string]entity.Invitation
+}
+
+func (r *fakeInvitationRepo) CreateInvitation(ctx context.Context, data entity.Invitation) error {
+	r.invitations[data.ID] = data
+	return nil
+}
+
+func (r *fakeInvitationRepo) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (entity.Invitation, error) {
+	for _, inv := range r.invitations {
+		if inv.TokenHash == tokenHash {
+			return inv, nil
+		}
+	}
+	return entity.Invitation{}, errTestNotFound
+}
+
+func (r *fakeInvitationRepo) RevokePendingByEmail(ctx context.Context, email string) error {
+	for id, inv := range r.invitations {
+		if inv.Email == email && inv.AcceptedAt == nil {
+			delete(r.invitations, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeInvitationRepo) MarkAccepted(ctx context.Context, id string) (bool, error) {
+	inv, ok := r.invitations[id]
+	if !ok || inv.AcceptedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	inv.AcceptedAt = &now
+	r.invitations[id] = inv
+	return true, nil
+}
+
+func (r *fakeInvitationRepo) Tx(ctx context.Context) repository.InvitationRepository { return r }
+
+type fakeOrganizationRepo struct {
+	repository.OrganizationRepository
+	orgs    map[string]entity.Organization
+	members map[string]entity.OrganizationMember
+}
+
+func (r *fakeOrganizationRepo) GetOrganizationByID(ctx context.Context, id string) (entity.Organization, error) {
+	org, ok := r.orgs[id]
+	if !ok {
+		return entity.Organization{}, errTestNotFound
+	}
+	return org, nil
+}
+
+func (r *fakeOrganizationRepo) GetMember(ctx context.Context, organizationID, userID string) (entity.OrganizationMember, error) {
+	m, ok := r.members[organizationID+"/"+userID]
+	if !ok {
+		return entity.OrganizationMember{}, errTestNotFound
+	}
+	return m, nil
+}
+
+func (r *fakeOrganizationRepo) AddMember(ctx context.Context, data entity.OrganizationMember) error {
+	r.members[data.OrganizationID+"/"+data.UserID] = data
+	return nil
+}
+
+func (r *fakeOrganizationRepo) ListMembershipsByUser(ctx context.Context, userID string) ([]entity.OrganizationMember, error) {
+	var members []entity.OrganizationMember
+	for _, m := range r.members {
+		if m.UserID == userID {
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+func (r *fakeOrganizationRepo) Tx(ctx context.Context) repository.OrganizationRepository { return r }
+
+type fakeOutboxRepo struct {
+	repository.OutboxRepository
+	messages []entity.OutboxMessage
+}
+
+func (r *fakeOutboxRepo) CreateOutboxMessage(ctx context.Context, data entity.OutboxMessage) error {
+	r.messages = append(r.messages, data)
+	return nil
+}
+
+func (r *fakeOutboxRepo) Tx(ctx context.Context) repository.OutboxRepository { return r }
+
+type fakeHasher struct{}
+
+func (fakeHasher) HashPassword(password string) (string, error) { return "hash:" + password, nil }
+
+func (fakeHasher) VerifyPassword(hash, password string) (bool, error) {
+	return hash == "hash:"+password, nil
+}
+
+type fakePasswordPolicy struct{}
+
+func (fakePasswordPolicy) Validate(password string) error { return nil }
+
+type fakePasswordHistory struct {
+	PasswordHistoryUsecase
+	saved []string
+}
+
+func (h *fakePasswordHistory) Save(ctx context.Context, userID, hash string) error {
+	h.saved = append(h.saved, userID)
+	return nil
+}
+
+type fakePublisher struct {
+	events []entity.UserEventType
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event entity.UserEvent) error {
+	p.events = append(p.events, event.Type)
+	return nil
+}
+
+type invitationFixture struct {
+	uc          InvitationUsecase
+	users       *fakeUserRepo
+	invitations *fakeInvitationRepo
+	orgs        *fakeOrganizationRepo
+	outbox      *fakeOutboxRepo
+	history     *fakePasswordHistory
+	publisher   *fakePublisher
+}
+
+func newInvitationFixture() *invitationFixture {
+	f := &invitationFixture{
+		users:       &fakeUserRepo{users: map[string]entity.User{"admin": {ID: "admin", Email: "admin@example.com"}}},
+		invitations: &fakeInvitationRepo{invitations: map[string]entity.Invitation{}},
+		orgs: &fakeOrganizationRepo{
+			orgs:    map[string]entity.Organization{"org-1": {ID: "org-1", Name: "Acme"}},
+			members: map[string]entity.OrganizationMember{"org-1/admin": {OrganizationID: "org-1", UserID: "admin", Role: entity.OrganizationRoleOwner}},
+		},
+		outbox:    &fakeOutboxRepo{},
+		history:   &fakePasswordHistory{},
+		publisher: &fakePublisher{},
+	}
+	f.uc = NewInvitationUsecase(f.invitations, f.users, f.orgs, f.outbox, fakeTx{}, fakeHasher{},
+		fakePasswordPolicy{}, f.history, f.publisher, InvitationPolicy{AcceptLink: "https://app.example.com/auth/invitation/"})
+	return f
+}
+
+func TestInviteProvisionsInvitee(t *testing.T) {
+	f := newInvitationFixture()
+	invitation, token, err := f.uc.Invite(context.Background(), InviteReq{
+		InviterID:      "admin",
+		Email:          " New.User@Example.com ",
+		FullName:       "New User",
+		OrganizationID: "org-1",
+	})
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	invitee, ok := f.users.users[invitation.UserID]
+	if !ok {
+		t.Fatal("invitee was not provisioned")
+	}
+	if invitee.Email != "new.user@example.com" || invitee.Password != "" || invitee.Veryfied != nil || invitee.CreatedBy != "admin" {
+		t.Fatalf("invitee = %+v, want unverified account without password created by admin", invitee)
+	}
+	if invitation.Role != entity.OrganizationRoleMember || !invitation.IsPending(time.Now()) {
+		t.Fatalf("invitation = %+v, want pending member invitation", invitation)
+	}
+	if invitation.TokenHash != hashInvitationToken(token) || strings.Contains(invitation.TokenHash, token) {
+		t.Fatal("only the token hash must be stored")
+	}
+	if len(f.outbox.messages) != 1 || !strings.Contains(string(f.outbox.messages[0].Payload), token) {
+		t.Fatalf("outbox = %+v, want one mail carrying the accept link", f.outbox.messages)
+	}
+}
+
+func TestInviteAgainReusesInvitee(t *testing.T) {
+	f := newInvitationFixture()
+	first, _, err := f.uc.Invite(context.Background(), InviteReq{InviterID: "admin", Email: "a@example.com"})
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	second, _, err := f.uc.Invite(context.Background(), InviteReq{InviterID: "admin", Email: "a@example.com"})
+	if err != nil {
+		t.Fatalf("Invite again: %v", err)
+	}
+	if second.UserID != first.UserID || len(f.users.users) != 2 {
+		t.Fatalf("re-invite provisioned another account: %+v", f.users.users)
+	}
+	if _, ok := f.invitations.invitations[first.ID]; ok || len(f.invitations.invitations) != 1 {
+		t.Fatal("previous pending invitation was not revoked")
+	}
+}
+
+func TestInviteRejects(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		req  InviteReq
+		want error
+	}{
+		{name: "invalid email", req: InviteReq{InviterID: "admin", Email: "not-an-email"}, want: ErrInvalidInvitationEmail},
+		{name: "verified email", req: InviteReq{InviterID: "admin", Email: "admin@example.com"}, want: ErrInvitationEmailExists},
+		{name: "role without organization", req: InviteReq{InviterID: "admin", Email: "a@example.com", Role: entity.OrganizationRoleAdmin}, want: ErrInvitationRoleNoOrg},
+		{name: "unknown organization", req: InviteReq{InviterID: "admin", Email: "a@example.com", OrganizationID: "org-2"}, want: ErrOrganizationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newInvitationFixture()
+			admin := f.users.users["admin"]
+			admin.Veryfied = &now
+			f.users.users["admin"] = admin
+			if _, _, err := f.uc.Invite(context.Background(), tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if len(f.outbox.messages) != 0 {
+				t.Fatal("rejected invitation must not send mail")
+			}
+		})
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	f := newInvitationFixture()
+	invitation, token, err := f.uc.Invite(context.Background(), InviteReq{InviterID: "admin", Email: "a@example.com", OrganizationID: "org-1", Role: entity.OrganizationRoleAdmin})
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	info, err := f.uc.AcceptInvitation(context.Background(), AcceptInvitationReq{
+		Token:           token,
+		FullName:        " A ",
+		Password:        "s3cret-password",
+		ConfirmPassword: "s3cret-password",
+	})
+	if err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	if info.ID != invitation.UserID || info.FullName != "A" {
+		t.Fatalf("info = %+v", info)
+	}
+	invitee := f.users.users[invitation.UserID]
+	if invitee.Password != "hash:s3cret-password" || invitee.Veryfied == nil {
+		t.Fatalf("invitee = %+v, want hashed password and verified", invitee)
+	}
+	if m, ok := f.orgs.members["org-1/"+invitee.ID]; !ok || m.Role != entity.OrganizationRoleAdmin {
+		t.Fatalf("member = %+v, want admin of org-1", m)
+	}
+	if invitee.ActiveOrganizationID == nil || *invitee.ActiveOrganizationID != "org-1" {
+		t.Fatal("org-1 was not made the active organization")
+	}
+	if len(f.history.saved) != 1 {
+		t.Fatal("password was not saved to history")
+	}
+	if len(f.publisher.events) != 2 || f.publisher.events[0] != entity.UserEventRegistered || f.publisher.events[1] != entity.UserEventVerified {
+		t.Fatalf("events = %v, want registered then verified", f.publisher.events)
+	}
+
+	// link chỉ dùng được một lần
+	_, err = f.uc.AcceptInvitation(context.Background(), AcceptInvitationReq{Token: token, Password: "x", ConfirmPassword: "x"})
+	if !errors.Is(err, ErrInvitationInvalid) {
+		t.Fatalf("second accept err = %v, want %v", err, ErrInvitationInvalid)
+	}
+}
+
+func TestAcceptInvitationRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(f *invitationFixture, inv *entity.Invitation)
+		req    AcceptInvitationReq
+		want   error
+	}{
+		{name: "empty password", req: AcceptInvitationReq{}, want: ErrInvitationNoPassword},
+		{name: "password mismatch", req: AcceptInvitationReq{Password: "a", ConfirmPassword: "b"}, want: ErrInvitationPassword},
+		{name: "unknown token", req: AcceptInvitationReq{Token: "unknown", Password: "a", ConfirmPassword: "a"}, want: ErrInvitationInvalid},
+		{
+			name: "expired",
+			mutate: func(f *invitationFixture, inv *entity.Invitation) {
+				inv.ExpiresAt = time.Now().Add(-time.Minute)
+			},
+			req:  AcceptInvitationReq{Password: "a", ConfirmPassword: "a"},
+			want: ErrInvitationInvalid,
+		},
+		{
+			name: "account already verified",
+			mutate: func(f *invitationFixture, inv *entity.Invitation) {
+				now := time.Now()
+				u := f.users.users[inv.UserID]
+				u.Veryfied = &now
+				f.users.users[inv.UserID] = u
+			},
+			req:  AcceptInvitationReq{Password: "a", ConfirmPassword: "a"},
+			want: ErrInvitationAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newInvitationFixture()
+			inv, token, err := f.uc.Invite(context.Background(), InviteReq{InviterID: "admin", Email: "a@example.com"})
+			if err != nil {
+				t.Fatalf("Invite: %v", err)
+			}
+			if tt.mutate != nil {
+				tt.mutate(f, &inv)
+				f.invitations.invitations[inv.ID] = inv
+			}
+			if tt.req.Token == "" {
+				tt.req.Token = token
+			}
+			if _, err := f.uc.AcceptInvitation(context.Background(), tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if f.users.users[inv.UserID].Password != "" || len(f.publisher.events) != 0 {
+				t.Fatal("rejected accept must not change the account")
+			}
+		})
+	}
+}
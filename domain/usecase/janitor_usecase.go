@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"auth-service/domain/repository"
+	"context"
+)
+
+const janitorBatchSize = 1000
+
+type JanitorUsecase interface {
+	PurgeExpiredSessions(ctx context.Context) (int, error)
+	PurgeExpiredInvitations(ctx context.Context) (int, error)
+}
+
+type janitorUsecaseImpl struct {
+	sessionRepo    repository.SessionRepository
+	invitationRepo repository.InvitationRepository
+}
+
+func NewJanitorUsecase(sessionRepo repository.SessionRepository, invitationRepo repository.InvitationRepository) JanitorUsecase {
+	return &janitorUsecaseImpl{
+		sessionRepo:    sessionRepo,
+		invitationRepo: invitationRepo,
+	}
+}
+
+func (uc *janitorUsecaseImpl) PurgeExpiredSessions(ctx context.Context) (int, error) {
+	return deleteInBatches(ctx, func(ctx context.Context) (int, error) {
+		return uc.sessionRepo.DeleteExpiredSessions(ctx, janitorBatchSize)
+	})
+}
+
+// PurgeExpiredInvitations xóa tài khoản tạo sẵn chưa dùng tới rồi xóa các lời mời hết hạn còn lại
+func (uc *janitorUsecaseImpl) PurgeExpiredInvitations(ctx context.Context) (int, error) {
+	invitees, err := deleteInBatches(ctx, func(ctx context.Context) (int, error) {
+		return uc.invitationRepo.DeleteExpiredInvitees(ctx, janitorBatchSize)
+	})
+	if err != nil {
+		return invitees, err
+	}
+	invitations, err := deleteInBatches(ctx, func(ctx context.Context) (int, error) {
+		return uc.invitationRepo.DeleteExpired(ctx, janitorBatchSize)
+	})
+	return invitees + invitations, err
+}
+
+// deleteInBatches xóa từng lô tới khi lô cuối không đầy
+func deleteInBatches(ctx context.Context, deleteBatch func(ctx context.Context) (int, error)) (int, error) {
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		n, err := deleteBatch(ctx)
+		total += n
+		if err != nil || n < janitorBatchSize {
+			return total, err
+		}
+	}
+}
@@ -3,11 +3,11 @@ package usecase
 import (
 	"auth-service/domain/entity"
 	"auth-service/domain/repository"
+	"context"
 	"errors"
 	"time"
 
 	"github.com/anhvanhoa/service-core/domain/cache"
-	hashpass "github.com/anhvanhoa/service-core/domain/hash_pass"
 	"github.com/anhvanhoa/service-core/domain/token"
 )
 
@@ -18,26 +18,32 @@ var (
 type LoginUsecase interface {
 	GetUserByEmailOrPhone(val string) (entity.User, error)
 	CheckHashPassword(password, hash string) bool
-	GengerateAccessToken(id, fullName, email string, exp time.Time) (string, error)
-	GengerateRefreshToken(id, fullName, email string, exp time.Time, os string) (string, error)
+	UpgradePasswordHash(user entity.User, password string) error
+	IsPasswordExpired(user entity.User) bool
+	GengerateAccessToken(id, fullName, email, organizationID string, exp time.Time) (string, error)
+	GengerateRefreshToken(id, fullName, email string, exp time.Time, device entity.Device) (string, error)
 }
 
 type loginUsecaseImpl struct {
-	userRepo    repository.UserRepository
-	sessionRepo repository.SessionRepository
-	jwtAccess   token.TokenAuthorizeI
-	jwtRefresh  token.TokenAuthorizeI
-	hassPass    hashpass.HashPassI
-	cache       cache.CacheI
+	userRepo       repository.UserRepository
+	sessionRepo    repository.SessionRepository
+	jwtAccess      repository.AccessTokenIssuer
+	jwtRefresh     token.TokenAuthorizeI
+	hassPass       repository.PasswordHasher
+	cache          cache.CacheI
+	refreshTokens  repository.TokenIndex
+	passwordMaxAge time.Duration
 }
 
 func NewLoginUsecase(
 	userRepo repository.UserRepository,
 	sessionRepo repository.SessionRepository,
-	jwtAccess token.TokenAuthorizeI,
+	jwtAccess repository.AccessTokenIssuer,
 	jwtRefresh token.TokenAuthorizeI,
-	hassPass hashpass.HashPassI,
+	hassPass repository.PasswordHasher,
 	cache cache.CacheI,
+	refreshTokens repository.TokenIndex,
+	passwordMaxAge time.Duration,
 ) LoginUsecase {
 	return &loginUsecaseImpl{
 		userRepo,
@@ -46,6 +52,8 @@ func NewLoginUsecase(
 		jwtRefresh,
 		hassPass,
 		cache,
+		refreshTokens,
+		passwordMaxAge,
 	}
 }
 
@@ -71,11 +79,29 @@ func (uc *loginUsecaseImpl) CheckHashPassword(password, hash string) bool {
 	return mach
 }
 
-func (uc *loginUsecaseImpl) GengerateAccessToken(id, fullName, email string, exp time.Time) (string, error) {
-	return uc.jwtAccess.GenAuthorizeToken(id, fullName, email, exp)
+// UpgradePasswordHash băm lại mật khẩu với thuật toán/tham số hiện tại khi hash
+// cũ đã lỗi thời; chỉ cập nhật nếu mật khẩu chưa bị đổi trong lúc đăng nhập.
+func (uc *loginUsecaseImpl) UpgradePasswordHash(user entity.User, password string) error {
+	if !uc.hassPass.NeedsRehash(user.Password) {
+		return nil
+	}
+	hash, err := uc.hassPass.HashPassword(password)
+	if err != nil {
+		return ErrHashPassword
+	}
+	_, err = uc.userRepo.UpdatePasswordHash(user.ID, user.Password, hash)
+	return err
+}
+
+func (uc *loginUsecaseImpl) IsPasswordExpired(user entity.User) bool {
+	return user.IsPasswordExpired(uc.passwordMaxAge)
 }
 
-func (uc *loginUsecaseImpl) GengerateRefreshToken(id, fullName, email string, exp time.Time, os string) (string, error) {
+func (uc *loginUsecaseImpl) GengerateAccessToken(id, fullName, email, organizationID string, exp time.Time) (string, error) {
+	return uc.jwtAccess.GenAuthorizeTokenWithOrganization(id, fullName, email, organizationID, exp)
+}
+
+func (uc *loginUsecaseImpl) GengerateRefreshToken(id, fullName, email string, exp time.Time, device entity.Device) (string, error) {
 	token, err := uc.jwtRefresh.GenAuthorizeToken(id, fullName, email, exp)
 	if err != nil {
 		return "", err
@@ -84,7 +110,9 @@ func (uc *loginUsecaseImpl) GengerateRefreshToken(id, fullName, email string, ex
 	session := entity.Session{
 		Token:     token,
 		UserID:    id,
-		Os:        os,
+		Os:        device.Os,
+		IP:        device.IP,
+		UserAgent: device.UserAgent,
 		Type:      (entity.SessionTypeAuth),
 		ExpiredAt: exp,
 		CreatedAt: time.Now(),
@@ -94,8 +122,10 @@ func (uc *loginUsecaseImpl) GengerateRefreshToken(id, fullName, email string, ex
 		if err := uc.sessionRepo.CreateSession(session); err != nil {
 			return "", err
 		}
-	} else {
-		go uc.sessionRepo.CreateSession(session)
+	} else if err := uc.sessionRepo.CreateSessionAsync(session); err != nil {
+		return "", err
+	} else if err := uc.refreshTokens.Add(context.Background(), id, token, exp); err != nil {
+		return "", err
 	}
 	return token, nil
 }
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"auth-service/domain/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	"github.com/anhvanhoa/service-core/domain/oops"
+)
+
+const (
+	notMeTokenLength = 32
+	notMeCachePrefix = "not_me:"
+)
+
+var ErrNotMeTokenInvalid = oops.New("Liên kết không hợp lệ, đã hết hạn hoặc đã được sử dụng")
+
+type notMeSession struct {
+	UserID       string `json:"user_id"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type NotMeUsecase interface {
+	// Issue tạo token cho link "không phải tôi" gắn với refresh token của lần đăng nhập lạ,
+	// link dùng được một lần và còn hiệu lực tới khi phiên đó hết hạn
+	Issue(ctx context.Context, userID, refreshToken string, exp time.Time) (string, error)
+	// Confirm thu hồi phiên gắn với token, buộc đổi mật khẩu và trả về token đổi mật khẩu dùng với ResetPasswordByToken
+	Confirm(ctx context.Context, token, os string) (string, error)
+}
+
+type notMeUsecaseImpl struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	cache       cache.CacheI
+	forceReset  ForcePasswordResetUsecase
+	forgot      ForgotPasswordUsecase
+}
+
+func NewNotMeUsecase(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	cache cache.CacheI,
+	forceReset ForcePasswordResetUsecase,
+	forgot ForgotPasswordUsecase,
+) NotMeUsecase {
+	return &notMeUsecaseImpl{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		cache:       cache,
+		forceReset:  forceReset,
+		forgot:      forgot,
+	}
+}
+
+func (uc *notMeUsecaseImpl) Issue(ctx context.Context, userID, refreshToken string, exp time.Time) (string, error) {
+	b := make([]byte, notMeTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	data, err := json.Marshal(notMeSession{
+		UserID:       userID,
+		RefreshToken: refreshToken,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := uc.cache.Set(notMeCacheKey(token), data, time.Until(exp)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Confirm xóa phiên của lần đăng nhập lạ rồi buộc đổi mật khẩu; buộc đổi mật khẩu thu hồi cả các phiên còn lại
+// vì refresh token xoay vòng từ phiên lạ không phân biệt được với phiên khác của user
+func (uc *notMeUsecaseImpl) Confirm(ctx context.Context, token, os string) (string, error) {
+	key := notMeCacheKey(token)
+	data, err := uc.cache.Get(key)
+	if err != nil || data == nil {
+		return "", ErrNotMeTokenInvalid
+	}
+	var session notMeSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return "", ErrNotMeTokenInvalid
+	}
+	if err := uc.cache.Delete(key); err != nil {
+		return "", err
+	}
+	if err := uc.cache.Delete(session.RefreshToken); err != nil {
+		return "", err
+	}
+	if err := uc.sessionRepo.DeleteSessionAuthByToken(ctx, session.RefreshToken); err != nil {
+		return "", err
+	}
+	if _, err := uc.forceReset.Execute(ctx, []string{session.UserID}); err != nil {
+		return "", err
+	}
+	user, err := uc.userRepo.GetUserByID(session.UserID)
+	if err != nil {
+		return "", ErrUserNotFound
+	}
+	res, err := uc.forgot.ForgotPassword(user.Email, os, ForgotByToken)
+	if err != nil {
+		return "", err
+	}
+	return res.Token, nil
+}
+
+func notMeCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return notMeCachePrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/oops"
+	"github.com/google/uuid"
+)
+
+type OrganizationNameScope string
+
+const (
+	// OrganizationNameGlobal tên tổ chức là duy nhất trên toàn hệ thống
+	OrganizationNameGlobal OrganizationNameScope = "global"
+	// OrganizationNameOwner tên tổ chức chỉ cần duy nhất trong các tổ chức do cùng một người tạo
+	OrganizationNameOwner OrganizationNameScope = "owner"
+)
+
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}[a-z0-9]$`)
+
+var (
+	ErrOrganizationNotFound      = oops.New("Không tìm thấy tổ chức")
+	ErrInvalidOrganizationName   = oops.New("Tên tổ chức không hợp lệ")
+	ErrInvalidOrganizationSlug   = oops.New("Slug tổ chức chỉ gồm chữ thường, số và dấu gạch ngang, dài 3-64 ký tự")
+	ErrOrganizationNameExists    = oops.New("Tên tổ chức đã được sử dụng")
+	ErrOrganizationSlugExists    = oops.New("Slug tổ chức đã được sử dụng")
+	ErrInvalidOrganizationRole   = oops.New("Vai trò trong tổ chức không hợp lệ")
+	ErrNotOrganizationMember     = oops.New("Người dùng không thuộc tổ chức")
+	ErrAlreadyOrganizationMember = oops.New("Người dùng đã là thành viên của tổ chức")
+	ErrOrganizationForbidden     = oops.New("Bạn không có quyền quản lý thành viên của tổ chức")
+	ErrSingleOrganization        = oops.New("Mỗi người dùng chỉ được thuộc một tổ chức")
+)
+
+// OrganizationPolicy: SingleMembership giới hạn mỗi người dùng thuộc tối đa một tổ chức
+type OrganizationPolicy struct {
+	NameScope        OrganizationNameScope
+	SingleMembership bool
+}
+
+type CreateOrganizationReq struct {
+	Name    string
+	Slug    string
+	OwnerID string
+}
+
+type AddOrganizationMemberReq struct {
+	OrganizationID string
+	// ActorID rỗng khi thao tác từ admin CLI, bỏ qua kiểm tra quyền
+	ActorID string
+	Email   string
+	Role    entity.OrganizationRole
+}
+
+type OrganizationUsecase interface {
+	Create(ctx context.Context, req CreateOrganizationReq) (entity.Organization, error)
+	AddMember(ctx context.Context, req AddOrganizationMemberReq) (entity.OrganizationMember, error)
+	SwitchActive(ctx context.Context, userID, organizationID string) error
+	ActiveOrganization(ctx context.Context, userID string) (string, error)
+	ListMemberships(ctx context.Context, userID string) ([]entity.OrganizationMember, error)
+}
+
+type organizationUsecaseImpl struct {
+	organizationRepo repository.OrganizationRepository
+	userRepo         repository.UserRepository
+	tx               repository.ManagerTransaction
+	policy           OrganizationPolicy
+}
+
+func NewOrganizationUsecase(
+	organizationRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	tx repository.ManagerTransaction,
+	policy OrganizationPolicy,
+) OrganizationUsecase {
+	if policy.NameScope != OrganizationNameOwner {
+		policy.NameScope = OrganizationNameGlobal
+	}
+	return &organizationUsecaseImpl{
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		tx:               tx,
+		policy:           policy,
+	}
+}
+
+// Create tạo tổ chức, người tạo trở thành owner và được chọn làm tổ chức hoạt động nếu chưa có
+func (uc *organizationUsecaseImpl) Create(ctx context.Context, req CreateOrganizationReq) (entity.Organization, error) {
+	name := strings.TrimSpace(req.Name)
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if name == "" || len(name) > 255 {
+		return entity.Organization{}, ErrInvalidOrganizationName
+	}
+	if !organizationSlugPattern.MatchString(slug) {
+		return entity.Organization{}, ErrInvalidOrganizationSlug
+	}
+	owner, err := uc.userRepo.GetUserByID(req.OwnerID)
+	if err != nil {
+		return entity.Organization{}, ErrUserNotFound
+	}
+	if err := uc.checkSingleMembership(ctx, owner.ID); err != nil {
+		return entity.Organization{}, err
+	}
+	scope := ""
+	if uc.policy.NameScope == OrganizationNameOwner {
+		scope = owner.ID
+	}
+	if exists, err := uc.organizationRepo.NameExists(ctx, name, scope); err != nil {
+		return entity.Organization{}, err
+	} else if exists {
+		return entity.Organization{}, ErrOrganizationNameExists
+	}
+	if exists, err := uc.organizationRepo.SlugExists(ctx, slug); err != nil {
+		return entity.Organization{}, err
+	} else if exists {
+		return entity.Organization{}, ErrOrganizationSlugExists
+	}
+
+	now := time.Now()
+	org := entity.Organization{
+		ID:        uuid.NewString(),
+		Name:      name,
+		NameKey:   organizationNameKey(name, scope),
+		Slug:      slug,
+		CreatedBy: owner.ID,
+		CreatedAt: now,
+	}
+	err = uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if err := uc.organizationRepo.Tx(ctx).CreateOrganization(ctx, org); err != nil {
+			return err
+		}
+		if err := uc.organizationRepo.Tx(ctx).AddMember(ctx, entity.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         owner.ID,
+			Role:           entity.OrganizationRoleOwner,
+			CreatedAt:      now,
+		}); err != nil {
+			return err
+		}
+		if owner.ActiveOrganizationID == nil {
+			return uc.userRepo.Tx(ctx).SetActiveOrganization(ctx, owner.ID, org.ID)
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, repository.ErrOrganizationNameTaken):
+		return entity.Organization{}, ErrOrganizationNameExists
+	case errors.Is(err, repository.ErrOrganizationSlugTaken):
+		return entity.Organization{}, ErrOrganizationSlugExists
+	case err != nil:
+		return entity.Organization{}, err
+	}
+	return org, nil
+}
+
+// organizationNameKey: NameExists chỉ kiểm tra trước, name_key unique mới chặn được hai request tạo cùng tên đồng thời
+func organizationNameKey(name, ownerID string) string {
+	key := strings.ToLower(name)
+	if ownerID != "" {
+		key = ownerID + "/" + key
+	}
+	return key
+}
+
+func (uc *organizationUsecaseImpl) AddMember(ctx context.Context, req AddOrganizationMemberReq) (entity.OrganizationMember, error) {
+	if req.Role == "" {
+		req.Role = entity.OrganizationRoleMember
+	}
+	if !req.Role.IsValid() {
+		return entity.OrganizationMember{}, ErrInvalidOrganizationRole
+	}
+	if _, err := uc.organizationRepo.GetOrganizationByID(ctx, req.OrganizationID); err != nil {
+		return entity.OrganizationMember{}, ErrOrganizationNotFound
+	}
+	if req.ActorID != "" {
+		actor, err := uc.organizationRepo.GetMember(ctx, req.OrganizationID, req.ActorID)
+		if err != nil || !actor.Role.CanManageMembers() {
+			return entity.OrganizationMember{}, ErrOrganizationForbidden
+		}
+		// chỉ owner mới được thêm owner khác
+		if req.Role == entity.OrganizationRoleOwner && actor.Role != entity.OrganizationRoleOwner {
+			return entity.OrganizationMember{}, ErrOrganizationForbidden
+		}
+	}
+	user, err := uc.userRepo.GetUserByEmail(req.Email)
+	if err != nil {
+		return entity.OrganizationMember{}, ErrUserNotFound
+	}
+	if _, err := uc.organizationRepo.GetMember(ctx, req.OrganizationID, user.ID); err == nil {
+		return entity.OrganizationMember{}, ErrAlreadyOrganizationMember
+	}
+	if err := uc.checkSingleMembership(ctx, user.ID); err != nil {
+		return entity.OrganizationMember{}, err
+	}
+
+	member := entity.OrganizationMember{
+		OrganizationID: req.OrganizationID,
+		UserID:         user.ID,
+		Role:           req.Role,
+		CreatedAt:      time.Now(),
+	}
+	err = uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if err := uc.organizationRepo.Tx(ctx).AddMember(ctx, member); err != nil {
+			return err
+		}
+		if user.ActiveOrganizationID == nil {
+			return uc.userRepo.Tx(ctx).SetActiveOrganization(ctx, user.ID, member.OrganizationID)
+		}
+		return nil
+	})
+	return member, err
+}
+
+// SwitchActive đổi tổ chức hoạt động, có hiệu lực với access token cấp từ lần đăng nhập/refresh tiếp theo
+func (uc *organizationUsecaseImpl) SwitchActive(ctx context.Context, userID, organizationID string) error {
+	if _, err := uc.organizationRepo.GetMember(ctx, organizationID, userID); err != nil {
+		return ErrNotOrganizationMember
+	}
+	return uc.userRepo.SetActiveOrganization(ctx, userID, organizationID)
+}
+
+func (uc *organizationUsecaseImpl) ActiveOrganization(ctx context.Context, userID string) (string, error) {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return "", ErrUserNotFound
+	}
+	if user.ActiveOrganizationID == nil {
+		return "", nil
+	}
+	return *user.ActiveOrganizationID, nil
+}
+
+func (uc *organizationUsecaseImpl) ListMemberships(ctx context.Context, userID string) ([]entity.OrganizationMember, error) {
+	return uc.organizationRepo.ListMembershipsByUser(ctx, userID)
+}
+
+func (uc *organizationUsecaseImpl) checkSingleMembership(ctx context.Context, userID string) error {
+	if !uc.policy.SingleMembership {
+		return nil
+	}
+	memberships, err := uc.organizationRepo.ListMembershipsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(memberships) > 0 {
+		return ErrSingleOrganization
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+// racingOrganizationRepo mô phỏng hai request cùng qua được NameExists/SlugExists trước khi request kia commit:
+// kiểm tra trước luôn báo chưa có, chỉ ràng buộc unique lúc insert phát hiện trùng
+type racingOrganizationRepo struct {
+	*fakeOrganizationRepo
+}
+
+func (r racingOrganizationRepo) NameExists(ctx context.Context, name, createdBy string) (bool, error) {
+	return false, nil
+}
+
+func (r racingOrganizationRepo) SlugExists(ctx context.Context, slug string) (bool, error) {
+	return false, nil
+}
+
+func (r racingOrganizationRepo) CreateOrganization(ctx context.Context, data entity.Organization) error {
+	for _, org := range r.orgs {
+		if org.NameKey == data.NameKey {
+			return repository.ErrOrganizationNameTaken
+		}
+		if org.Slug == data.Slug {
+			return repository.ErrOrganizationSlugTaken
+		}
+	}
+	r.orgs[data.ID] = data
+	return nil
+}
+
+func (r racingOrganizationRepo) Tx(ctx context.Context) repository.OrganizationRepository { return r }
+
+func newRacingOrganizationUsecase(scope OrganizationNameScope) (OrganizationUsecase, *fakeUserRepo) {
+	users := &fakeUserRepo{users: map[string]entity.User{
+		"alice": {ID: "alice", Email: "alice@example.com"},
+		"bob":   {ID: "bob", Email: "bob@example.com"},
+	}}
+	orgs := racingOrganizationRepo{&fakeOrganizationRepo{
+		orgs:    map[string]entity.Organization{},
+		members: map[string]entity.OrganizationMember{},
+	}}
+	return NewOrganizationUsecase(orgs, users, fakeTx{}, OrganizationPolicy{NameScope: scope}), users
+}
+
+func TestCreateOrganizationNameRace(t *testing.T) {
+	tests := []struct {
+		name   string
+		scope  OrganizationNameScope
+		second CreateOrganizationReq
+		want   error
+	}{
+		{name: "global same name", scope: OrganizationNameGlobal, second: CreateOrganizationReq{Name: "ACME", Slug: "acme-2", OwnerID: "bob"}, want: ErrOrganizationNameExists},
+		{name: "owner same owner", scope: OrganizationNameOwner, second: CreateOrganizationReq{Name: "acme", Slug: "acme-2", OwnerID: "alice"}, want: ErrOrganizationNameExists},
+		{name: "owner other owner", scope: OrganizationNameOwner, second: CreateOrganizationReq{Name: "Acme", Slug: "acme-2", OwnerID: "bob"}, want: nil},
+		{name: "same slug", scope: OrganizationNameGlobal, second: CreateOrganizationReq{Name: "Other", Slug: "acme", OwnerID: "bob"}, want: ErrOrganizationSlugExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, users := newRacingOrganizationUsecase(tt.scope)
+			first, err := uc.Create(context.Background(), CreateOrganizationReq{Name: "Acme", Slug: "acme", OwnerID: "alice"})
+			if err != nil {
+				t.Fatalf("first Create: %v", err)
+			}
+			if got := users.users["alice"].ActiveOrganizationID; got == nil || *got != first.ID {
+				t.Fatal("owner's active organization was not set")
+			}
+			org, err := uc.Create(context.Background(), tt.second)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("second Create err = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil && org.ID != "" {
+				t.Fatalf("second Create returned %+v with error", org)
+			}
+		})
+	}
+}
+
+func TestOrganizationNameKey(t *testing.T) {
+	if organizationNameKey("Acme", "") != organizationNameKey("ACME", "") {
+		t.Error("global key is case sensitive")
+	}
+	if organizationNameKey("Acme", "alice") == organizationNameKey("Acme", "bob") {
+		t.Error("owner key does not depend on the owner")
+	}
+	if organizationNameKey("Acme", "alice") == organizationNameKey("Acme", "") {
+		t.Error("owner key collides with the global key")
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrUnknownOutboxKind = errors.New("unknown outbox message kind")
+
+const (
+	outboxBatchSize   = 50
+	outboxLease       = time.Minute
+	outboxMaxAttempts = 10
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+type OutboxRelayUsecase interface {
+	Relay(ctx context.Context) (int, error)
+	DeleteDelivered(ctx context.Context, retention time.Duration) (int, error)
+}
+
+type outboxRelayUsecaseImpl struct {
+	outboxRepo repository.OutboxRepository
+	mailSender repository.MailSender
+	publisher  repository.EventPublisher
+}
+
+func NewOutboxRelayUsecase(
+	outboxRepo repository.OutboxRepository,
+	mailSender repository.MailSender,
+	publisher repository.EventPublisher,
+) OutboxRelayUsecase {
+	return &outboxRelayUsecaseImpl{
+		outboxRepo: outboxRepo,
+		mailSender: mailSender,
+		publisher:  publisher,
+	}
+}
+
+// Relay gửi một lô message đến hạn, trả về số message đã gửi thành công.
+// Message lỗi được hẹn lại theo exponential backoff và chuyển sang failed khi hết số lần thử.
+func (uc *outboxRelayUsecaseImpl) Relay(ctx context.Context) (int, error) {
+	messages, err := uc.outboxRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, msg := range messages {
+		if err := uc.deliver(ctx, &msg); err != nil {
+			if err := uc.retry(ctx, msg, err); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		if err := uc.outboxRepo.MarkDelivered(ctx, msg.ID); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func (uc *outboxRelayUsecaseImpl) deliver(ctx context.Context, msg *entity.OutboxMessage) error {
+	switch msg.Kind {
+	case entity.OutboxKindEvent:
+		var event entity.UserEvent
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			return err
+		}
+		return uc.publisher.Publish(ctx, event)
+	case entity.OutboxKindMail:
+		var mail entity.OutboxMail
+		if err := json.Unmarshal(msg.Payload, &mail); err != nil {
+			return err
+		}
+		// id của message là task id nên enqueue lại sau khi lỗi bị queue từ chối như task trùng;
+		// task_id đã lưu thì lần thử sau chỉ ghi lại lịch sử
+		if msg.TaskID == "" {
+			taskID, err := uc.mailSender.Enqueue(ctx, msg.ID, msg.Topic, mail)
+			if err != nil {
+				return err
+			}
+			if err := uc.outboxRepo.SetTaskID(ctx, msg.ID, taskID); err != nil {
+				return err
+			}
+			msg.TaskID = taskID
+		}
+		return uc.mailSender.CreateHistory(ctx, msg.TaskID, msg.Topic, mail)
+	}
+	return ErrUnknownOutboxKind
+}
+
+func (uc *outboxRelayUsecaseImpl) retry(ctx context.Context, msg entity.OutboxMessage, cause error) error {
+	attempts := msg.Attempts + 1
+	if attempts >= outboxMaxAttempts {
+		return uc.outboxRepo.MarkFailed(ctx, msg.ID, attempts, cause.Error())
+	}
+	backoff := outboxBaseBackoff << (attempts - 1)
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return uc.outboxRepo.MarkRetry(ctx, msg.ID, attempts, time.Now().Add(backoff), cause.Error())
+}
+
+func (uc *outboxRelayUsecaseImpl) DeleteDelivered(ctx context.Context, retention time.Duration) (int, error) {
+	return uc.outboxRepo.DeleteDeliveredBefore(ctx, time.Now().Add(-retention))
+}
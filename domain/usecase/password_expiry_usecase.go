@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"auth-service/domain/repository"
+	"context"
+)
+
+// PasswordExpiryUsecase bật/tắt hết hạn mật khẩu theo password_expiry_days cho từng tài khoản,
+// thường là tài khoản quản trị; tài khoản chưa bật không bao giờ bị hết hạn mật khẩu.
+type PasswordExpiryUsecase interface {
+	SetEnabled(ctx context.Context, userIDs []string, enabled bool) (int, error)
+}
+
+type passwordExpiryUsecaseImpl struct {
+	userRepo repository.UserRepository
+}
+
+func NewPasswordExpiryUsecase(userRepo repository.UserRepository) PasswordExpiryUsecase {
+	return &passwordExpiryUsecaseImpl{
+		userRepo: userRepo,
+	}
+}
+
+func (uc *passwordExpiryUsecaseImpl) SetEnabled(ctx context.Context, userIDs []string, enabled bool) (int, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	return uc.userRepo.SetPasswordExpires(ctx, userIDs, enabled)
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"time"
+
+	hashpass "github.com/anhvanhoa/service-core/domain/hash_pass"
+	"github.com/anhvanhoa/service-core/domain/oops"
+)
+
+var (
+	ErrPasswordReused = oops.New("Mật khẩu mới không được trùng với các mật khẩu đã sử dụng gần đây")
+)
+
+type PasswordHistoryUsecase interface {
+	CheckReused(userID, password string) error
+	Save(ctx context.Context, userID, hash string) error
+}
+
+type passwordHistoryUsecaseImpl struct {
+	historyRepo repository.PasswordHistoryRepository
+	hashPass    hashpass.HashPassI
+	limit       int
+}
+
+func NewPasswordHistoryUsecase(
+	historyRepo repository.PasswordHistoryRepository,
+	hashPass hashpass.HashPassI,
+	limit int,
+) PasswordHistoryUsecase {
+	return &passwordHistoryUsecaseImpl{
+		historyRepo: historyRepo,
+		hashPass:    hashPass,
+		limit:       limit,
+	}
+}
+
+func (uc *passwordHistoryUsecaseImpl) CheckReused(userID, password string) error {
+	if uc.limit <= 0 {
+		return nil
+	}
+	histories, err := uc.historyRepo.GetRecentByUserID(userID, uc.limit)
+	if err != nil {
+		return err
+	}
+	for _, h := range histories {
+		if match, err := uc.hashPass.VerifyPassword(h.Password, password); err == nil && match {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+func (uc *passwordHistoryUsecaseImpl) Save(ctx context.Context, userID, hash string) error {
+	if uc.limit <= 0 {
+		return nil
+	}
+	repo := uc.historyRepo.Tx(ctx)
+	if err := repo.CreatePasswordHistory(ctx, entity.PasswordHistory{
+		UserID:    userID,
+		Password:  hash,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+	return repo.PruneByUserID(ctx, userID, uc.limit)
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"auth-service/domain/repository"
+
+	"github.com/anhvanhoa/service-core/domain/oops"
+)
+
+var (
+	ErrPasswordBreached = oops.New("Mật khẩu đã xuất hiện trong các vụ rò rỉ dữ liệu, vui lòng chọn mật khẩu khác")
+)
+
+type PasswordPolicyUsecase interface {
+	Validate(password string) error
+}
+
+type passwordPolicyUsecaseImpl struct {
+	breachedRepo repository.BreachedPasswordRepository
+}
+
+func NewPasswordPolicyUsecase(breachedRepo repository.BreachedPasswordRepository) PasswordPolicyUsecase {
+	return &passwordPolicyUsecaseImpl{
+		breachedRepo: breachedRepo,
+	}
+}
+
+func (uc *passwordPolicyUsecaseImpl) Validate(password string) error {
+	if uc.breachedRepo.IsBreached(password) {
+		return ErrPasswordBreached
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	"github.com/anhvanhoa/service-core/domain/oops"
+	"github.com/google/uuid"
+)
+
+const (
+	// PersonalAccessTokenPrefix giúp interceptor phân biệt personal access token với JWT
+	PersonalAccessTokenPrefix      = "pat_"
+	personalAccessTokenLength      = 32
+	personalAccessTokenShownPrefix = 8
+	maxPersonalAccessTokenName     = 100
+	personalAccessTokenCachePrefix = "pat:"
+)
+
+var (
+	ErrInvalidTokenName            = oops.New("Tên token không hợp lệ")
+	ErrTokenNameExists             = oops.New("Tên token đã được sử dụng")
+	ErrInvalidTokenScope           = oops.New("Scope của token phải có dạng resource.action, resource.* hoặc *")
+	ErrInvalidTokenExpiry          = oops.New("Thời điểm hết hạn của token phải ở tương lai")
+	ErrPersonalAccessTokenNotFound = oops.New("Không tìm thấy token")
+)
+
+var ErrPersonalAccessTokenInvalid = errors.New("personal access token is invalid, expired or revoked")
+
+type CreatePersonalAccessTokenReq struct {
+	UserID string
+	Name   string
+	Scopes []string
+	// ExpiresAt nil là token không hết hạn
+	ExpiresAt *time.Time
+}
+
+type PersonalAccessTokenUsecase interface {
+	// Create trả về token chưa băm, token chỉ hiển thị một lần lúc tạo
+	Create(ctx context.Context, req CreatePersonalAccessTokenReq) (entity.PersonalAccessToken, string, error)
+	List(ctx context.Context, userID string) ([]entity.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID, id string) error
+	// Authenticate kiểm tra token còn hiệu lực, chủ token còn hoạt động và ghi nhận lần dùng
+	Authenticate(ctx context.Context, token string) (entity.PersonalAccessToken, entity.User, error)
+}
+
+type personalAccessTokenUsecaseImpl struct {
+	tokenRepo repository.PersonalAccessTokenRepository
+	userRepo  repository.UserRepository
+	cache     cache.CacheI
+}
+
+func NewPersonalAccessTokenUsecase(
+	tokenRepo repository.PersonalAccessTokenRepository,
+	userRepo repository.UserRepository,
+	cache cache.CacheI,
+) PersonalAccessTokenUsecase {
+	return &personalAccessTokenUsecaseImpl{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		cache:     cache,
+	}
+}
+
+func (uc *personalAccessTokenUsecaseImpl) Create(ctx context.Context, req CreatePersonalAccessTokenReq) (entity.PersonalAccessToken, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxPersonalAccessTokenName {
+		return entity.PersonalAccessToken{}, "", ErrInvalidTokenName
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return entity.PersonalAccessToken{}, "", err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return entity.PersonalAccessToken{}, "", ErrInvalidTokenExpiry
+	}
+	if _, err := uc.userRepo.GetUserByID(req.UserID); err != nil {
+		return entity.PersonalAccessToken{}, "", ErrUserNotFound
+	}
+	if exists, err := uc.tokenRepo.NameExists(ctx, req.UserID, name); err != nil {
+		return entity.PersonalAccessToken{}, "", err
+	} else if exists {
+		return entity.PersonalAccessToken{}, "", ErrTokenNameExists
+	}
+
+	b := make([]byte, personalAccessTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return entity.PersonalAccessToken{}, "", err
+	}
+	secret := hex.EncodeToString(b)
+	token := entity.PersonalAccessToken{
+		ID:        uuid.NewString(),
+		UserID:    req.UserID,
+		Name:      name,
+		TokenHash: HashPersonalAccessToken(PersonalAccessTokenPrefix + secret),
+		Prefix:    PersonalAccessTokenPrefix + secret[:personalAccessTokenShownPrefix],
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.tokenRepo.CreateToken(ctx, token); err != nil {
+		return entity.PersonalAccessToken{}, "", err
+	}
+	return token, PersonalAccessTokenPrefix + secret, nil
+}
+
+func (uc *personalAccessTokenUsecaseImpl) List(ctx context.Context, userID string) ([]entity.PersonalAccessToken, error) {
+	return uc.tokenRepo.ListTokensByUser(ctx, userID)
+}
+
+// Revoke xóa cả quyền đã cache để token mất hiệu lực ngay
+func (uc *personalAccessTokenUsecaseImpl) Revoke(ctx context.Context, userID, id string) error {
+	token, ok, err := uc.tokenRepo.RevokeToken(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPersonalAccessTokenNotFound
+	}
+	return uc.cache.Delete(PersonalAccessTokenCacheKey(token.TokenHash))
+}
+
+func (uc *personalAccessTokenUsecaseImpl) Authenticate(ctx context.Context, raw string) (entity.PersonalAccessToken, entity.User, error) {
+	token, err := uc.tokenRepo.GetTokenByHash(ctx, HashPersonalAccessToken(raw))
+	if err != nil || !token.IsActive(time.Now()) {
+		return entity.PersonalAccessToken{}, entity.User{}, ErrPersonalAccessTokenInvalid
+	}
+	user, err := uc.userRepo.GetUserByID(token.UserID)
+	if err != nil || user.Veryfied == nil || user.IsSuspended() {
+		return entity.PersonalAccessToken{}, entity.User{}, ErrPersonalAccessTokenInvalid
+	}
+	// last_used_at chỉ để hiển thị nên bỏ qua lỗi
+	uc.tokenRepo.TouchLastUsed(ctx, token.ID)
+	return token, user, nil
+}
+
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+func HashPersonalAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PersonalAccessTokenCacheKey là key lưu UserContext của token, không dùng token gốc làm key
+func PersonalAccessTokenCacheKey(tokenHash string) string {
+	return personalAccessTokenCachePrefix + tokenHash
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == entity.ScopeAll {
+			normalized = append(normalized, scope)
+			continue
+		}
+		resource, action, ok := strings.Cut(scope, ".")
+		if !ok || resource == "" || action == "" || strings.ContainsAny(scope, " \t") {
+			return nil, ErrInvalidTokenScope
+		}
+		normalized = append(normalized, scope)
+	}
+	if len(normalized) == 0 {
+		return nil, ErrInvalidTokenScope
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
@@ -4,38 +4,54 @@ import (
 	"auth-service/domain/entity"
 	"auth-service/domain/repository"
 	"context"
+	"errors"
 	"time"
 
 	"github.com/anhvanhoa/service-core/domain/cache"
 	"github.com/anhvanhoa/service-core/domain/token"
 )
 
+var (
+	ErrPasswordResetRequired = errors.New("mật khẩu cần được đổi trước khi tiếp tục")
+	ErrUserSuspended         = errors.New("tài khoản đã bị khóa")
+)
+
 type RefreshUsecase interface {
 	CheckSessionByToken(token string) bool
-	ClearSessionExpired() error
+	// GetUser đọc lại user để token mới không được cấp cho tài khoản đã bị khóa hoặc bị yêu cầu đổi mật khẩu
+	GetUser(id string) (entity.User, error)
 	VerifyToken(token string) (*token.AuthorizeClaims, error)
-	GengerateAccessToken(id, fullName, email string, exp time.Time) (string, error)
-	GengerateRefreshToken(id, fullName, email string, exp time.Time, os string) (string, error)
+	GengerateAccessToken(id, fullName, email, organizationID string, exp time.Time) (string, error)
+	GengerateRefreshToken(id, fullName, email string, exp time.Time, device entity.Device) (string, error)
 }
 
 type refreshUsecaseImpl struct {
-	sessionRepo repository.SessionRepository
-	access      token.TokenAuthorizeI
-	refresh     token.TokenAuthorizeI
-	cache       cache.CacheI
+	userRepo      repository.UserRepository
+	sessionRepo   repository.SessionRepository
+	access        repository.AccessTokenIssuer
+	refresh       token.TokenAuthorizeI
+	cache         cache.CacheI
+	refreshTokens repository.TokenIndex
+	workers       repository.WorkerGroup
 }
 
 func NewRefreshUsecase(
+	userRepo repository.UserRepository,
 	sessionRepo repository.SessionRepository,
-	access token.TokenAuthorizeI,
+	access repository.AccessTokenIssuer,
 	refresh token.TokenAuthorizeI,
 	cache cache.CacheI,
+	refreshTokens repository.TokenIndex,
+	workers repository.WorkerGroup,
 ) RefreshUsecase {
 	return &refreshUsecaseImpl{
-		sessionRepo: sessionRepo,
-		access:      access,
-		refresh:     refresh,
-		cache:       cache,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		access:        access,
+		refresh:       refresh,
+		cache:         cache,
+		refreshTokens: refreshTokens,
+		workers:       workers,
 	}
 }
 
@@ -44,20 +60,25 @@ func (uc *refreshUsecaseImpl) CheckSessionByToken(token string) bool {
 		if _, err := uc.sessionRepo.GetSessionAliveByToken(entity.SessionTypeAuth, token); err != nil {
 			return false
 		}
-		go func() {
-			if err := uc.sessionRepo.DeleteSessionAuthByToken(context.Background(), token); err != nil {
-				return
-			}
-		}()
+		uc.workers.Go("delete_auth_session", func(ctx context.Context) error {
+			return uc.sessionRepo.DeleteSessionAuthByToken(ctx, token)
+		})
 	}
 	return true
 }
 
-func (uc *refreshUsecaseImpl) ClearSessionExpired() error {
-	if err := uc.sessionRepo.DeleteAllSessionsExpired(context.Background()); err != nil {
-		return err
+func (uc *refreshUsecaseImpl) GetUser(id string) (entity.User, error) {
+	user, err := uc.userRepo.GetUserByID(id)
+	if err != nil {
+		return entity.User{}, ErrUserNotFound
+	}
+	if user.IsSuspended() {
+		return entity.User{}, ErrUserSuspended
 	}
-	return nil
+	if user.PasswordResetRequired {
+		return entity.User{}, ErrPasswordResetRequired
+	}
+	return user, nil
 }
 
 func (uc *refreshUsecaseImpl) VerifyToken(token string) (*token.AuthorizeClaims, error) {
@@ -68,11 +89,11 @@ func (uc *refreshUsecaseImpl) VerifyToken(token string) (*token.AuthorizeClaims,
 	return claims, nil
 }
 
-func (uc *refreshUsecaseImpl) GengerateAccessToken(id, fullName, email string, exp time.Time) (string, error) {
-	return uc.access.GenAuthorizeToken(id, fullName, email, exp)
+func (uc *refreshUsecaseImpl) GengerateAccessToken(id, fullName, email, organizationID string, exp time.Time) (string, error) {
+	return uc.access.GenAuthorizeTokenWithOrganization(id, fullName, email, organizationID, exp)
 }
 
-func (uc *refreshUsecaseImpl) GengerateRefreshToken(id, fullName, email string, exp time.Time, os string) (string, error) {
+func (uc *refreshUsecaseImpl) GengerateRefreshToken(id, fullName, email string, exp time.Time, device entity.Device) (string, error) {
 	token, err := uc.refresh.GenAuthorizeToken(id, fullName, email, exp)
 	if err != nil {
 		return "", err
@@ -81,7 +102,9 @@ func (uc *refreshUsecaseImpl) GengerateRefreshToken(id, fullName, email string,
 	session := entity.Session{
 		Token:     token,
 		UserID:    id,
-		Os:        os,
+		Os:        device.Os,
+		IP:        device.IP,
+		UserAgent: device.UserAgent,
 		Type:      entity.SessionTypeAuth,
 		ExpiredAt: exp,
 		CreatedAt: time.Now(),
@@ -91,8 +114,10 @@ func (uc *refreshUsecaseImpl) GengerateRefreshToken(id, fullName, email string,
 		if err := uc.sessionRepo.CreateSession(session); err != nil {
 			return "", err
 		}
-	} else {
-		go uc.sessionRepo.CreateSession(session)
+	} else if err := uc.sessionRepo.CreateSessionAsync(session); err != nil {
+		return "", err
+	} else if err := uc.refreshTokens.Add(context.Background(), id, token, exp); err != nil {
+		return "", err
 	}
 	return token, nil
 }
@@ -13,7 +13,6 @@ import (
 	hashpass "github.com/anhvanhoa/service-core/domain/hash_pass"
 
 	"github.com/anhvanhoa/service-core/domain/goid"
-	"github.com/anhvanhoa/service-core/domain/queue"
 	"github.com/anhvanhoa/service-core/domain/saga"
 	"github.com/anhvanhoa/service-core/domain/token"
 )
@@ -29,31 +28,33 @@ type RegisterReq struct {
 	Password        string
 	ConfirmPassword string
 	Code            string
+	VerifyLink      string
 }
 
 type RegisterUsecase interface {
 	CheckUserExist(email string) (bool, error)
 	hashPassword(password string) (string, error)
 	Register(user RegisterReq, os string, exp time.Time) (ResRegister, error)
-	RegisterWithSaga(sagaID string, execute common.ExecuteSaga) error
+	RegisterWithSaga(ctx context.Context, sagaID string, execute common.ExecuteSaga) error
 	GengerateCode(length int8) string
 	createOrUpdateUser(user RegisterReq, ctx context.Context) (entity.UserInfor, error)
-	saveToken(token string, id string, os string) error
-	SendMail(payload queue.PayloadI) (string, error)
+	saveToken(ctx context.Context, token string, id string, os string) error
 	CompensateRegister(ctx context.Context, userId string, token string) error
-	CompensateSendMail(ctx context.Context, taskID string) error
 }
 
 type registerUsecaseImpl struct {
-	userRepo    repository.UserRepository
-	sessionRepo repository.SessionRepository
-	jwt         token.TokenAuthI
-	tx          repository.ManagerTransaction
-	saga        saga.SagaManager
-	goid        goid.GoUUID
-	hashPass    hashpass.HashPassI
-	cache       cache.CacheI
-	qc          queue.QueueClient
+	userRepo        repository.UserRepository
+	sessionRepo     repository.SessionRepository
+	jwt             token.TokenAuthI
+	tx              repository.ManagerTransaction
+	saga            saga.SagaManager
+	goid            goid.GoUUID
+	hashPass        hashpass.HashPassI
+	cache           cache.CacheI
+	outboxRepo      repository.OutboxRepository
+	passwordHistory PasswordHistoryUsecase
+	publisher       repository.EventPublisher
+	workers         repository.WorkerGroup
 }
 
 func NewRegisterUsecase(
@@ -64,19 +65,25 @@ func NewRegisterUsecase(
 	goid goid.GoUUID,
 	hashPass hashpass.HashPassI,
 	cache cache.CacheI,
-	queue queue.QueueClient,
+	outboxRepo repository.OutboxRepository,
 	saga saga.SagaManager,
+	passwordHistory PasswordHistoryUsecase,
+	publisher repository.EventPublisher,
+	workers repository.WorkerGroup,
 ) RegisterUsecase {
 	return &registerUsecaseImpl{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		tx:          tx,
-		jwt:         jwt,
-		goid:        goid,
-		hashPass:    hashPass,
-		cache:       cache,
-		qc:          queue,
-		saga:        saga,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		tx:              tx,
+		jwt:             jwt,
+		goid:            goid,
+		hashPass:        hashPass,
+		cache:           cache,
+		outboxRepo:      outboxRepo,
+		saga:            saga,
+		passwordHistory: passwordHistory,
+		publisher:       publisher,
+		workers:         workers,
 	}
 }
 
@@ -105,30 +112,55 @@ func (uc *registerUsecaseImpl) Register(user RegisterReq, os string, exp time.Ti
 		if res.UserInfor, err = uc.createOrUpdateUser(user, ctx); err != nil {
 			return err
 		}
-		if err = uc.sessionRepo.DeleteSessionVerifyByUserID(ctx, res.UserInfor.ID); err != nil {
+		if err = uc.sessionRepo.Tx(ctx).DeleteSessionVerifyByUserID(ctx, res.UserInfor.ID); err != nil {
 			return err
 		}
 		if res.Token, err = uc.jwt.GenAuthToken(res.UserInfor.ID, user.Code, exp); err != nil {
 			return err
 		}
-		if err = uc.saveToken(res.Token, res.UserInfor.ID, os); err != nil {
+		if err = uc.saveToken(ctx, res.Token, res.UserInfor.ID, os); err != nil {
 			return err
 		}
-		return nil
+		mail, err := entity.NewOutboxMail(constants.TPL_REGISTER_MAIL, entity.OutboxMail{
+			Tos: []string{res.UserInfor.Email},
+			Data: map[string]any{
+				"user": res.UserInfor,
+				"link": user.VerifyLink + res.Token,
+			},
+			Message: "Send mail to " + res.UserInfor.Email,
+		})
+		if err != nil {
+			return err
+		}
+		if err = uc.outboxRepo.Tx(ctx).CreateOutboxMessage(ctx, mail); err != nil {
+			return err
+		}
+		return uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventRegistered, res.UserInfor.ID, entity.UserRegisteredData{
+			Email:    res.UserInfor.Email,
+			FullName: res.UserInfor.FullName,
+		}))
 	})
-	return res, err
+	if err != nil {
+		return res, err
+	}
+	// Session đã nằm trong DB, cache chỉ để tra cứu nhanh nên bỏ qua lỗi
+	uc.cache.Set(res.Token, []byte(constants.TPL_VERIFY_MAIL), constants.VerifyExpiredAt*time.Second)
+	return res, nil
 }
 
 func (uc *registerUsecaseImpl) createOrUpdateUser(user RegisterReq, ctx context.Context) (entity.UserInfor, error) {
 	var userInfo entity.UserInfor
 	var err error
 	id := uc.goid.Gen()
+	now := time.Now()
 	newUser := entity.User{
-		ID:         id,
-		Email:      user.Email,
-		Password:   user.Password,
-		FullName:   user.FullName,
-		CodeVerify: user.Code,
+		ID:                id,
+		Email:             user.Email,
+		Password:          user.Password,
+		FullName:          user.FullName,
+		CodeVerify:        user.Code,
+		PasswordChangedAt: &now,
+		RegisteredAt:      &now,
 	}
 	if newUser.Password, err = uc.hashPassword(newUser.Password); err != nil {
 		return userInfo, err
@@ -150,10 +182,13 @@ func (uc *registerUsecaseImpl) createOrUpdateUser(user RegisterReq, ctx context.
 			return userInfo, err
 		}
 	}
+	if err := uc.passwordHistory.Save(ctx, userInfo.ID, newUser.Password); err != nil {
+		return userInfo, err
+	}
 	return userInfo, nil
 }
 
-func (uc *registerUsecaseImpl) saveToken(token string, userId string, os string) error {
+func (uc *registerUsecaseImpl) saveToken(ctx context.Context, token string, userId string, os string) error {
 	session := entity.Session{
 		Token:     token,
 		UserID:    userId,
@@ -162,23 +197,12 @@ func (uc *registerUsecaseImpl) saveToken(token string, userId string, os string)
 		CreatedAt: time.Now(),
 		ExpiredAt: time.Now().Add(constants.VerifyExpiredAt * time.Second),
 	}
-	if err := uc.cache.Set(token, []byte(constants.TPL_VERIFY_MAIL), constants.VerifyExpiredAt*time.Second); err != nil {
-		if err := uc.sessionRepo.CreateSession(session); err != nil {
-			return err
-		}
-	} else {
-		go uc.sessionRepo.CreateSession(session)
-	}
-	return nil
+	return uc.sessionRepo.Tx(ctx).CreateSession(session)
 }
 
-func (uc *registerUsecaseImpl) SendMail(payload queue.PayloadI) (string, error) {
-	return uc.qc.EnqueueAnyTask(payload)
-}
-
-func (uc *registerUsecaseImpl) RegisterWithSaga(sagaID string, execute common.ExecuteSaga) error {
-	ctx := context.Background()
-	sagaTx := uc.saga.NewTransaction(sagaID, ctx)
+func (uc *registerUsecaseImpl) RegisterWithSaga(ctx context.Context, sagaID string, execute common.ExecuteSaga) error {
+	// Giữ span/trace của request nhưng không để client hủy request làm dừng saga giữa chừng
+	sagaTx := uc.saga.NewTransaction(sagaID, context.WithoutCancel(ctx))
 	if err := execute(sagaTx.GetContext(), sagaTx); err != nil {
 		return err
 	}
@@ -186,13 +210,18 @@ func (uc *registerUsecaseImpl) RegisterWithSaga(sagaID string, execute common.Ex
 }
 
 func (uc *registerUsecaseImpl) CompensateRegister(ctx context.Context, userID string, token string) error {
-	if err := uc.sessionRepo.DeleteSessionVerifyByUserID(ctx, userID); err != nil {
-		return err
-	}
-	go uc.cache.Delete(token)
-	return uc.userRepo.DeleteByID(ctx, userID)
-}
-
-func (uc *registerUsecaseImpl) CompensateSendMail(ctx context.Context, taskID string) error {
-	return uc.qc.CancelTask(taskID)
+	uc.workers.Go("delete_verify_cache", func(ctx context.Context) error {
+		return uc.cache.Delete(token)
+	})
+	return uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if err := uc.sessionRepo.Tx(ctx).DeleteSessionVerifyByUserID(ctx, userID); err != nil {
+			return err
+		}
+		if err := uc.userRepo.Tx(ctx).DeleteByID(ctx, userID); err != nil {
+			return err
+		}
+		return uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventDeleted, userID, entity.UserDeletedData{
+			Reason: "registration_rollback",
+		}))
+	})
 }
@@ -17,11 +17,16 @@ type ResetPasswordByCodeUsecase interface {
 }
 
 type ResetPasswordByCodeUsecaseImpl struct {
-	userRepo    repository.UserRepository
-	sessionRepo repository.SessionRepository
-	cache       cache.CacheI
-	jwt         token.TokenForgotPasswordI
-	hashPass    hashpass.HashPassI
+	userRepo        repository.UserRepository
+	sessionRepo     repository.SessionRepository
+	tx              repository.ManagerTransaction
+	cache           cache.CacheI
+	jwt             token.TokenForgotPasswordI
+	hashPass        hashpass.HashPassI
+	passwordHistory PasswordHistoryUsecase
+	publisher       repository.EventPublisher
+	revocation      TokenRevocationUsecase
+	workers         repository.WorkerGroup
 }
 
 var (
@@ -33,16 +38,26 @@ var (
 func NewResetPasswordCodeUsecase(
 	userRepo repository.UserRepository,
 	sessionRepo repository.SessionRepository,
+	tx repository.ManagerTransaction,
 	cache cache.CacheI,
 	token token.TokenForgotPasswordI,
 	hashPass hashpass.HashPassI,
+	passwordHistory PasswordHistoryUsecase,
+	publisher repository.EventPublisher,
+	revocation TokenRevocationUsecase,
+	workers repository.WorkerGroup,
 ) ResetPasswordByCodeUsecase {
 	return &ResetPasswordByCodeUsecaseImpl{
 		userRepo,
 		sessionRepo,
+		tx,
 		cache,
 		token,
 		hashPass,
+		passwordHistory,
+		publisher,
+		revocation,
+		workers,
 	}
 }
 
@@ -57,21 +72,39 @@ func (uc *ResetPasswordByCodeUsecaseImpl) VerifySession(code, email string) (str
 			return "", ErrNotFoundSession
 		}
 	}
-	go func() {
-		uc.sessionRepo.DeleteSessionForgotByTokenAndIdUser(context.Background(), code, user.ID)
+	uc.workers.Go("delete_forgot_session", func(ctx context.Context) error {
 		uc.cache.Delete(key)
-	}()
+		return uc.sessionRepo.DeleteSessionForgotByTokenAndIdUser(ctx, code, user.ID)
+	})
 	return user.ID, nil
 }
 
 func (uc *ResetPasswordByCodeUsecaseImpl) ResetPass(IdUser, Password, ConfirmPassword string) error {
+	if err := uc.passwordHistory.CheckReused(IdUser, ConfirmPassword); err != nil {
+		return err
+	}
+
 	ConfirmPassword, err := uc.hashPass.HashPassword(ConfirmPassword)
 	if err != nil {
 		return ErrHashPassword
 	}
 
-	if _, err = uc.userRepo.UpdateUser(IdUser, entity.User{Password: ConfirmPassword}); err != nil {
-		return ErrUpdatePassword
+	err = uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if err := uc.userRepo.Tx(ctx).UpdatePassword(IdUser, ConfirmPassword); err != nil {
+			return ErrUpdatePassword
+		}
+		if err := uc.sessionRepo.Tx(ctx).DeleteSessionByTypeAndUserID(ctx, entity.SessionTypeAuth, IdUser); err != nil {
+			return err
+		}
+		if err := uc.passwordHistory.Save(ctx, IdUser, ConfirmPassword); err != nil {
+			return err
+		}
+		return uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventPasswordChanged, IdUser, entity.UserPasswordChangedData{
+			Method: "reset_code",
+		}))
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+	return uc.revocation.RevokeUsers(context.Background(), IdUser)
 }
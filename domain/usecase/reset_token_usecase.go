@@ -16,26 +16,41 @@ type ResetPasswordByTokenUsecase interface {
 }
 
 type ResetPasswordByTokenUsecaseImpl struct {
-	userRepo    repository.UserRepository
-	sessionRepo repository.SessionRepository
-	cache       cache.CacheI
-	jwt         token.TokenForgotPasswordI
-	hashPass    hashpass.HashPassI
+	userRepo        repository.UserRepository
+	sessionRepo     repository.SessionRepository
+	tx              repository.ManagerTransaction
+	cache           cache.CacheI
+	jwt             token.TokenForgotPasswordI
+	hashPass        hashpass.HashPassI
+	passwordHistory PasswordHistoryUsecase
+	publisher       repository.EventPublisher
+	revocation      TokenRevocationUsecase
+	workers         repository.WorkerGroup
 }
 
 func NewResetPasswordTokenUsecase(
 	userRepo repository.UserRepository,
 	sessionRepo repository.SessionRepository,
+	tx repository.ManagerTransaction,
 	cache cache.CacheI,
 	token token.TokenForgotPasswordI,
 	hashPass hashpass.HashPassI,
+	passwordHistory PasswordHistoryUsecase,
+	publisher repository.EventPublisher,
+	revocation TokenRevocationUsecase,
+	workers repository.WorkerGroup,
 ) ResetPasswordByTokenUsecase {
 	return &ResetPasswordByTokenUsecaseImpl{
 		userRepo,
 		sessionRepo,
+		tx,
 		cache,
 		token,
 		hashPass,
+		passwordHistory,
+		publisher,
+		revocation,
+		workers,
 	}
 }
 
@@ -45,10 +60,10 @@ func (uc *ResetPasswordByTokenUsecaseImpl) VerifySession(token string) (string,
 			return "", ErrNotFoundSession
 		}
 	}
-	go func() {
-		uc.sessionRepo.DeleteSessionForgotByToken(context.Background(), token)
+	uc.workers.Go("delete_forgot_session", func(ctx context.Context) error {
 		uc.cache.Delete(token)
-	}()
+		return uc.sessionRepo.DeleteSessionForgotByToken(ctx, token)
+	})
 	claim, err := uc.jwt.VerifyForgotPasswordToken(token)
 	if err != nil {
 		return "", err
@@ -63,13 +78,31 @@ func (uc *ResetPasswordByTokenUsecaseImpl) ResetPass(IdUser, Password, ConfirmPa
 		return ErrNotFoundUser
 	}
 
+	if err := uc.passwordHistory.CheckReused(user.ID, ConfirmPassword); err != nil {
+		return err
+	}
+
 	ConfirmPassword, err = uc.hashPass.HashPassword(ConfirmPassword)
 	if err != nil {
 		return ErrHashPassword
 	}
 
-	if _, err = uc.userRepo.UpdateUser(user.ID, entity.User{Password: ConfirmPassword}); err != nil {
-		return ErrUpdatePassword
+	err = uc.tx.RunInTransaction(func(ctx context.Context) error {
+		if err := uc.userRepo.Tx(ctx).UpdatePassword(user.ID, ConfirmPassword); err != nil {
+			return ErrUpdatePassword
+		}
+		if err := uc.sessionRepo.Tx(ctx).DeleteSessionByTypeAndUserID(ctx, entity.SessionTypeAuth, user.ID); err != nil {
+			return err
+		}
+		if err := uc.passwordHistory.Save(ctx, user.ID, ConfirmPassword); err != nil {
+			return err
+		}
+		return uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventPasswordChanged, user.ID, entity.UserPasswordChangedData{
+			Method: "reset_token",
+		}))
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+	return uc.revocation.RevokeUsers(context.Background(), user.ID)
 }
@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/oops"
+	"github.com/google/uuid"
+)
+
+const (
+	serviceAccountSecretPrefix  = "sas_"
+	serviceAccountSecretLength  = 32
+	serviceAccountTokenLifetime = 15 * time.Minute
+	maxServiceAccountName       = 100
+)
+
+var (
+	ErrInvalidServiceAccountName   = oops.New("Tên service account không hợp lệ")
+	ErrServiceAccountOwner         = oops.New("Service account phải thuộc về đúng một người dùng hoặc một tổ chức")
+	ErrServiceAccountNotFound      = oops.New("Không tìm thấy service account")
+	ErrServiceAccountForbidden     = oops.New("Bạn không có quyền quản lý service account này")
+	ErrServiceAccountKeyNotFound   = oops.New("Không tìm thấy khóa của service account")
+	ErrInvalidServiceAccountKey    = oops.New("Public key phải là khóa RSA, ECDSA hoặc Ed25519 dạng PEM")
+	ErrServiceAccountCredentials   = oops.New("Thông tin xác thực service account không hợp lệ")
+	ErrInvalidServiceAccountExpiry = oops.New("Thời điểm hết hạn của khóa phải ở tương lai")
+)
+
+type CreateServiceAccountReq struct {
+	// ActorID rỗng khi thao tác từ admin CLI, bỏ qua kiểm tra quyền
+	ActorID             string
+	Name                string
+	Description         string
+	OwnerUserID         string
+	OwnerOrganizationID string
+}
+
+type IssueServiceAccountTokenReq struct {
+	KeyID string
+	// Secret dùng cho khóa dạng secret, Assertion là JWT ký bằng private key cho khóa dạng public key
+	Secret    string
+	Assertion string
+}
+
+type ServiceAccountUsecase interface {
+	Create(ctx context.Context, req CreateServiceAccountReq) (entity.ServiceAccount, error)
+	List(ctx context.Context, filter repository.ServiceAccountFilter) ([]entity.ServiceAccount, error)
+	SetDisabled(ctx context.Context, actorID, id string, disabled bool) error
+	// CreateSecret trả về secret chưa băm, secret chỉ hiển thị một lần lúc tạo
+	CreateSecret(ctx context.Context, actorID, serviceAccountID string, expiresAt *time.Time) (entity.ServiceAccountKey, string, error)
+	AddPublicKey(ctx context.Context, actorID, serviceAccountID, publicKeyPEM string, expiresAt *time.Time) (entity.ServiceAccountKey, error)
+	ListKeys(ctx context.Context, actorID, serviceAccountID string) ([]entity.ServiceAccountKey, error)
+	RevokeKey(ctx context.Context, actorID, serviceAccountID, keyID string) error
+	IssueToken(ctx context.Context, req IssueServiceAccountTokenReq) (string, time.Time, error)
+	// Authenticate trả về service account còn hoạt động, dùng khi interceptor nhận token của service account
+	Authenticate(ctx context.Context, id string) (entity.ServiceAccount, error)
+}
+
+type serviceAccountUsecaseImpl struct {
+	serviceAccountRepo repository.ServiceAccountRepository
+	userRepo           repository.UserRepository
+	organizationRepo   repository.OrganizationRepository
+	tokens             repository.AccessTokenIssuer
+	assertions         repository.ClientAssertionVerifier
+	usedAssertions     repository.NonceStore
+}
+
+func NewServiceAccountUsecase(
+	serviceAccountRepo repository.ServiceAccountRepository,
+	userRepo repository.UserRepository,
+	organizationRepo repository.OrganizationRepository,
+	tokens repository.AccessTokenIssuer,
+	assertions repository.ClientAssertionVerifier,
+	usedAssertions repository.NonceStore,
+) ServiceAccountUsecase {
+	return &serviceAccountUsecaseImpl{
+		serviceAccountRepo: serviceAccountRepo,
+		userRepo:           userRepo,
+		organizationRepo:   organizationRepo,
+		tokens:             tokens,
+		assertions:         assertions,
+		usedAssertions:     usedAssertions,
+	}
+}
+
+func (uc *serviceAccountUsecaseImpl) Create(ctx context.Context, req CreateServiceAccountReq) (entity.ServiceAccount, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxServiceAccountName {
+		return entity.ServiceAccount{}, ErrInvalidServiceAccountName
+	}
+	if (req.OwnerUserID == "") == (req.OwnerOrganizationID == "") {
+		return entity.ServiceAccount{}, ErrServiceAccountOwner
+	}
+	if req.OwnerUserID != "" {
+		if _, err := uc.userRepo.GetUserByID(req.OwnerUserID); err != nil {
+			return entity.ServiceAccount{}, ErrUserNotFound
+		}
+	} else if _, err := uc.organizationRepo.GetOrganizationByID(ctx, req.OwnerOrganizationID); err != nil {
+		return entity.ServiceAccount{}, ErrOrganizationNotFound
+	}
+	account := entity.ServiceAccount{
+		ID:                  uuid.NewString(),
+		Name:                name,
+		Description:         strings.TrimSpace(req.Description),
+		OwnerUserID:         req.OwnerUserID,
+		OwnerOrganizationID: req.OwnerOrganizationID,
+		CreatedBy:           req.ActorID,
+		CreatedAt:           time.Now(),
+	}
+	if err := uc.checkManage(ctx, account, req.ActorID); err != nil {
+		return entity.ServiceAccount{}, err
+	}
+	if err := uc.serviceAccountRepo.CreateServiceAccount(ctx, account); err != nil {
+		return entity.ServiceAccount{}, err
+	}
+	return account, nil
+}
+
+func (uc *serviceAccountUsecaseImpl) List(ctx context.Context, filter repository.ServiceAccountFilter) ([]entity.ServiceAccount, error) {
+	return uc.serviceAccountRepo.ListServiceAccounts(ctx, filter)
+}
+
+// SetDisabled có hiệu lực với token đã cấp khi quyền cache của token hết hạn (tối đa một phút)
+func (uc *serviceAccountUsecaseImpl) SetDisabled(ctx context.Context, actorID, id string, disabled bool) error {
+	if _, err := uc.managed(ctx, actorID, id); err != nil {
+		return err
+	}
+	return uc.serviceAccountRepo.SetDisabled(ctx, id, disabled)
+}
+
+func (uc *serviceAccountUsecaseImpl) CreateSecret(ctx context.Context, actorID, serviceAccountID string, expiresAt *time.Time) (entity.ServiceAccountKey, string, error) {
+	if _, err := uc.managed(ctx, actorID, serviceAccountID); err != nil {
+		return entity.ServiceAccountKey{}, "", err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return entity.ServiceAccountKey{}, "", ErrInvalidServiceAccountExpiry
+	}
+	b := make([]byte, serviceAccountSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return entity.ServiceAccountKey{}, "", err
+	}
+	secret := serviceAccountSecretPrefix + hex.EncodeToString(b)
+	key := entity.ServiceAccountKey{
+		ID:               uuid.NewString(),
+		ServiceAccountID: serviceAccountID,
+		Type:             entity.ServiceAccountKeySecret,
+		SecretHash:       hashServiceAccountSecret(secret),
+		ExpiresAt:        expiresAt,
+		CreatedAt:        time.Now(),
+	}
+	if err := uc.serviceAccountRepo.CreateKey(ctx, key); err != nil {
+		return entity.ServiceAccountKey{}, "", err
+	}
+	return key, secret, nil
+}
+
+func (uc *serviceAccountUsecaseImpl) AddPublicKey(ctx context.Context, actorID, serviceAccountID, publicKeyPEM string, expiresAt *time.Time) (entity.ServiceAccountKey, error) {
+	if _, err := uc.managed(ctx, actorID, serviceAccountID); err != nil {
+		return entity.ServiceAccountKey{}, err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return entity.ServiceAccountKey{}, ErrInvalidServiceAccountExpiry
+	}
+	if err := uc.assertions.ValidatePublicKey(publicKeyPEM); err != nil {
+		return entity.ServiceAccountKey{}, ErrInvalidServiceAccountKey
+	}
+	key := entity.ServiceAccountKey{
+		ID:               uuid.NewString(),
+		ServiceAccountID: serviceAccountID,
+		Type:             entity.ServiceAccountKeyPublicKey,
+		PublicKey:        publicKeyPEM,
+		ExpiresAt:        expiresAt,
+		CreatedAt:        time.Now(),
+	}
+	if err := uc.serviceAccountRepo.CreateKey(ctx, key); err != nil {
+		return entity.ServiceAccountKey{}, err
+	}
+	return key, nil
+}
+
+func (uc *serviceAccountUsecaseImpl) ListKeys(ctx context.Context, actorID, serviceAccountID string) ([]entity.ServiceAccountKey, error) {
+	if _, err := uc.managed(ctx, actorID, serviceAccountID); err != nil {
+		return nil, err
+	}
+	return uc.serviceAccountRepo.ListKeys(ctx, serviceAccountID)
+}
+
+func (uc *serviceAccountUsecaseImpl) RevokeKey(ctx context.Context, actorID, serviceAccountID, keyID string) error {
+	if _, err := uc.managed(ctx, actorID, serviceAccountID); err != nil {
+		return err
+	}
+	ok, err := uc.serviceAccountRepo.RevokeKey(ctx, serviceAccountID, keyID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrServiceAccountKeyNotFound
+	}
+	return nil
+}
+
+// IssueToken cấp access token ngắn hạn cho service account từ secret hoặc assertion ký bằng private key,
+// assertion chỉ dùng được một lần
+func (uc *serviceAccountUsecaseImpl) IssueToken(ctx context.Context, req IssueServiceAccountTokenReq) (string, time.Time, error) {
+	keyID := req.KeyID
+	if keyID == "" && req.Assertion != "" {
+		var err error
+		if keyID, err = uc.assertions.KeyID(req.Assertion); err != nil {
+			return "", time.Time{}, ErrServiceAccountCredentials
+		}
+	}
+	key, err := uc.serviceAccountRepo.GetKeyByID(ctx, keyID)
+	if err != nil || !key.IsActive(time.Now()) {
+		return "", time.Time{}, ErrServiceAccountCredentials
+	}
+	switch key.Type {
+	case entity.ServiceAccountKeySecret:
+		if req.Secret == "" || subtle.ConstantTimeCompare([]byte(hashServiceAccountSecret(req.Secret)), []byte(key.SecretHash)) != 1 {
+			return "", time.Time{}, ErrServiceAccountCredentials
+		}
+	case entity.ServiceAccountKeyPublicKey:
+		if err := uc.consumeAssertion(ctx, key, req.Assertion); err != nil {
+			return "", time.Time{}, err
+		}
+	default:
+		return "", time.Time{}, ErrServiceAccountCredentials
+	}
+	account, err := uc.Authenticate(ctx, key.ServiceAccountID)
+	if err != nil {
+		return "", time.Time{}, ErrServiceAccountCredentials
+	}
+	// last_used_at chỉ để hiển thị nên bỏ qua lỗi
+	uc.serviceAccountRepo.TouchKeyLastUsed(ctx, key.ID)
+
+	exp := time.Now().Add(serviceAccountTokenLifetime)
+	token, err := uc.tokens.GenServiceAccountToken(account.ID, account.Name, account.Email(), account.OwnerOrganizationID, exp)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, exp, nil
+}
+
+// consumeAssertion chấp nhận mỗi jti một lần; ghi nhận jti là nguyên tử nên gửi lại cùng assertion đồng thời
+// cũng chỉ cấp được một token
+func (uc *serviceAccountUsecaseImpl) consumeAssertion(ctx context.Context, key entity.ServiceAccountKey, assertion string) error {
+	if assertion == "" {
+		return ErrServiceAccountCredentials
+	}
+	jti, exp, err := uc.assertions.Verify(assertion, key.ID, key.PublicKey)
+	if err != nil {
+		return ErrServiceAccountCredentials
+	}
+	claimed, err := uc.usedAssertions.Claim(ctx, key.ID+":"+jti, time.Until(exp))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrServiceAccountCredentials
+	}
+	return nil
+}
+
+func (uc *serviceAccountUsecaseImpl) Authenticate(ctx context.Context, id string) (entity.ServiceAccount, error) {
+	account, err := uc.serviceAccountRepo.GetServiceAccountByID(ctx, id)
+	if err != nil || account.IsDisabled() {
+		return entity.ServiceAccount{}, ErrServiceAccountNotFound
+	}
+	return account, nil
+}
+
+func (uc *serviceAccountUsecaseImpl) managed(ctx context.Context, actorID, id string) (entity.ServiceAccount, error) {
+	account, err := uc.serviceAccountRepo.GetServiceAccountByID(ctx, id)
+	if err != nil {
+		return entity.ServiceAccount{}, ErrServiceAccountNotFound
+	}
+	return account, uc.checkManage(ctx, account, actorID)
+}
+
+// checkManage: service account của người dùng do chính người đó quản lý,
+// của tổ chức thì owner/admin của tổ chức quản lý
+func (uc *serviceAccountUsecaseImpl) checkManage(ctx context.Context, account entity.ServiceAccount, actorID string) error {
+	if actorID == "" {
+		return nil
+	}
+	if account.OwnerUserID != "" {
+		if account.OwnerUserID != actorID {
+			return ErrServiceAccountForbidden
+		}
+		return nil
+	}
+	member, err := uc.organizationRepo.GetMember(ctx, account.OwnerOrganizationID, actorID)
+	if err != nil || !member.Role.CanManageMembers() {
+		return ErrServiceAccountForbidden
+	}
+	return nil
+}
+
+func hashServiceAccountSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
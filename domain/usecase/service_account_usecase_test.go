@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"auth-service/infrastructure/accesstoken"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testAudience = "auth-service"
+
+type fakeServiceAccountRepo struct {
+	repository.ServiceAccountRepository
+	accounts map[string]entity.ServiceAccount
+	keys     map[string]entity.ServiceAccountKey
+}
+
+func (r *fakeServiceAccountRepo) GetServiceAccountByID(ctx context.Context, id string) (entity.ServiceAccount, error) {
+	a, ok := r.accounts[id]
+	if !ok {
+		return entity.ServiceAccount{}, errTestNotFound
+	}
+	return a, nil
+}
+
+func (r *fakeServiceAccountRepo) GetKeyByID(ctx context.Context, id string) (entity.ServiceAccountKey, error) {
+	k, ok := r.keys[id]
+	if !ok {
+		return entity.ServiceAccountKey{}, errTestNotFound
+	}
+	return k, nil
+}
+
+func (r *fakeServiceAccountRepo) TouchKeyLastUsed(ctx context.Context, id string) error { return nil }
+
+type fakeIssuer struct {
+	repository.AccessTokenIssuer
+}
+
+func (fakeIssuer) GenServiceAccountToken(id, name, email, organizationID string, exp time.Time) (string, error) {
+	return "token-" + id, nil
+}
+
+// memoryNonceStore giữ jti trong bộ nhớ, Claim nguyên tử nhờ mutex như SETNX của Redis
+type memoryNonceStore struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func (s *memoryNonceStore) Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exp, ok := s.seen[nonce]; ok && time.Now().Before(exp) {
+		return false, nil
+	}
+	s.seen[nonce] = time.Now().Add(ttl)
+	return true, nil
+}
+
+type serviceAccountFixture struct {
+	uc      ServiceAccountUsecase
+	repo    *fakeServiceAccountRepo
+	private ed25519.PrivateKey
+}
+
+func newServiceAccountFixture(t *testing.T) *serviceAccountFixture {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	repo := &fakeServiceAccountRepo{
+		accounts: map[string]entity.ServiceAccount{"sa-1": {ID: "sa-1", Name: "ci"}},
+		keys: map[string]entity.ServiceAccountKey{
+			"key-pem": {
+				ID:               "key-pem",
+				ServiceAccountID: "sa-1",
+				Type:             entity.ServiceAccountKeyPublicKey,
+				PublicKey:        string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			},
+			"key-secret": {
+				ID:               "key-secret",
+				ServiceAccountID: "sa-1",
+				Type:             entity.ServiceAccountKeySecret,
+				SecretHash:       hashServiceAccountSecret("s3cret"),
+			},
+		},
+	}
+	uc := NewServiceAccountUsecase(repo, nil, nil, fakeIssuer{},
+		accesstoken.NewClientAssertionVerifier(testAudience), &memoryNonceStore{seen: map[string]time.Time{}})
+	return &serviceAccountFixture{uc: uc, repo: repo, private: private}
+}
+
+func (f *serviceAccountFixture) assertion(t *testing.T, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = "key-pem"
+	s, err := token.SignedString(f.private)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return s
+}
+
+func validClaims(jti string, lifetime time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Issuer:    "key-pem",
+		Subject:   "key-pem",
+		Audience:  jwt.ClaimStrings{testAudience},
+		ID:        jti,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+	}
+}
+
+func TestIssueTokenWithAssertion(t *testing.T) {
+	f := newServiceAccountFixture(t)
+	assertion := f.assertion(t, validClaims("jti-1", time.Minute))
+
+	start := time.Now()
+	token, exp, err := f.uc.IssueToken(context.Background(), IssueServiceAccountTokenReq{Assertion: assertion})
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	if token != "token-sa-1" {
+		t.Fatalf("token = %q", token)
+	}
+	if d := exp.Sub(start); d < serviceAccountTokenLifetime || d > serviceAccountTokenLifetime+5*time.Second {
+		t.Fatalf("token lifetime = %s, want %s", d, serviceAccountTokenLifetime)
+	}
+
+	// gửi lại cùng assertion (cùng jti) bị từ chối
+	if _, _, err := f.uc.IssueToken(context.Background(), IssueServiceAccountTokenReq{Assertion: assertion}); !errors.Is(err, ErrServiceAccountCredentials) {
+		t.Fatalf("replay err = %v, want %v", err, ErrServiceAccountCredentials)
+	}
+	// jti mới vẫn dùng được
+	if _, _, err := f.uc.IssueToken(context.Background(), IssueServiceAccountTokenReq{Assertion: f.assertion(t, validClaims("jti-2", time.Minute))}); err != nil {
+		t.Fatalf("IssueToken with new jti: %v", err)
+	}
+}
+
+func TestIssueTokenConcurrentReplay(t *testing.T) {
+	f := newServiceAccountFixture(t)
+	assertion := f.assertion(t, validClaims("jti-1", time.Minute))
+	var issued atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := f.uc.IssueToken(context.Background(), IssueServiceAccountTokenReq{Assertion: assertion}); err == nil {
+				issued.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := issued.Load(); got != 1 {
+		t.Fatalf("issued %d tokens for one assertion, want 1", got)
+	}
+}
+
+func TestIssueTokenRejectsAssertion(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		claims func() jwt.RegisteredClaims
+	}{
+		{name: "lifetime over the maximum", claims: func() jwt.RegisteredClaims {
+			return validClaims("jti", 10*time.Minute)
+		}},
+		{name: "expired", claims: func() jwt.RegisteredClaims {
+			c := validClaims("jti", time.Minute)
+			c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+			return c
+		}},
+		{name: "no expiry", claims: func() jwt.RegisteredClaims {
+			c := validClaims("jti", time.Minute)
+			c.ExpiresAt = nil
+			return c
+		}},
+		{name: "no jti", claims: func() jwt.RegisteredClaims {
+			return validClaims("", time.Minute)
+		}},
+		{name: "other audience", claims: func() jwt.RegisteredClaims {
+			c := validClaims("jti", time.Minute)
+			c.Audience = jwt.ClaimStrings{"other-service"}
+			return c
+		}},
+		{name: "issuer is not the key", claims: func() jwt.RegisteredClaims {
+			c := validClaims("jti", time.Minute)
+			c.Issuer = "key-secret"
+			return c
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceAccountFixture(t)
+			_, _, err := f.uc.IssueToken(context.Background(), IssueServiceAccountTokenReq{Assertion: f.assertion(t, tt.claims())})
+			if !errors.Is(err, ErrServiceAccountCredentials) {
+				t.Fatalf("err = %v, want %v", err, ErrServiceAccountCredentials)
+			}
+		})
+	}
+}
+
+func TestIssueTokenRejects(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name   string
+		mutate func(f *serviceAccountFixture)
+		req    IssueServiceAccountTokenReq
+	}{
+		{name: "wrong secret", req: IssueServiceAccountTokenReq{KeyID: "key-secret", Secret: "wrong"}},
+		{name: "empty secret", req: IssueServiceAccountTokenReq{KeyID: "key-secret"}},
+		{name: "unknown key", req: IssueServiceAccountTokenReq{KeyID: "missing", Secret: "s3cret"}},
+		{
+			name: "revoked key",
+			mutate: func(f *serviceAccountFixture) {
+				k := f.repo.keys["key-secret"]
+				k.RevokedAt = &past
+				f.repo.keys["key-secret"] = k
+			},
+			req: IssueServiceAccountTokenReq{KeyID: "key-secret", Secret: "s3cret"},
+		},
+		{
+			name: "disabled account",
+			mutate: func(f *serviceAccountFixture) {
+				a := f.repo.accounts["sa-1"]
+				a.DisabledAt = &past
+				f.repo.accounts["sa-1"] = a
+			},
+			req: IssueServiceAccountTokenReq{KeyID: "key-secret", Secret: "s3cret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceAccountFixture(t)
+			if _, _, err := f.uc.IssueToken(context.Background(), IssueServiceAccountTokenReq{KeyID: "key-secret", Secret: "s3cret"}); err != nil {
+				t.Fatalf("IssueToken with valid secret: %v", err)
+			}
+			if tt.mutate != nil {
+				tt.mutate(f)
+			}
+			if _, _, err := f.uc.IssueToken(context.Background(), tt.req); !errors.Is(err, ErrServiceAccountCredentials) {
+				t.Fatalf("err = %v, want %v", err, ErrServiceAccountCredentials)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+)
+
+type SuspendUserUsecase interface {
+	Execute(ctx context.Context, userIDs []string, reason string) (int, error)
+}
+
+type suspendUserUsecaseImpl struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	tx          repository.ManagerTransaction
+	publisher   repository.EventPublisher
+	revocation  TokenRevocationUsecase
+}
+
+func NewSuspendUserUsecase(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	tx repository.ManagerTransaction,
+	publisher repository.EventPublisher,
+	revocation TokenRevocationUsecase,
+) SuspendUserUsecase {
+	return &suspendUserUsecaseImpl{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		tx:          tx,
+		publisher:   publisher,
+		revocation:  revocation,
+	}
+}
+
+func (uc *suspendUserUsecaseImpl) Execute(ctx context.Context, userIDs []string, reason string) (int, error) {
+	var suspended []string
+	err := uc.tx.RunInTransaction(func(ctx context.Context) error {
+		var err error
+		if suspended, err = uc.userRepo.Tx(ctx).UpdateStatus(ctx, userIDs, entity.UserStatusInactive); err != nil {
+			return err
+		}
+		for _, id := range suspended {
+			if err := uc.sessionRepo.Tx(ctx).DeleteSessionByTypeAndUserID(ctx, entity.SessionTypeAuth, id); err != nil {
+				return err
+			}
+			if err := uc.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventSuspended, id, entity.UserSuspendedData{
+				Reason: reason,
+			})); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(suspended), uc.revocation.RevokeUsers(ctx, suspended...)
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+)
+
+// TokenRevocationUsecase xóa refresh token và access token đang cache của user.
+// Chỉ xóa session trong DB là chưa đủ: RefreshToken tra Redis trước DB, interceptor chỉ đọc quyền đã cache
+// theo access token, nên token vẫn dùng được tới khi hết hạn nếu không xóa trong cache.
+type TokenRevocationUsecase interface {
+	// RevokeUsers gọi sau khi transaction xóa session trong DB đã commit
+	RevokeUsers(ctx context.Context, userIDs ...string) error
+}
+
+type tokenRevocationUsecaseImpl struct {
+	cache         cache.CacheI
+	refreshTokens repository.TokenIndex
+	accessTokens  repository.TokenIndex
+}
+
+func NewTokenRevocationUsecase(
+	cache cache.CacheI,
+	refreshTokens repository.TokenIndex,
+	accessTokens repository.TokenIndex,
+) TokenRevocationUsecase {
+	return &tokenRevocationUsecaseImpl{
+		cache:         cache,
+		refreshTokens: refreshTokens,
+		accessTokens:  accessTokens,
+	}
+}
+
+func (uc *tokenRevocationUsecaseImpl) RevokeUsers(ctx context.Context, userIDs ...string) error {
+	var errs []error
+	for _, id := range userIDs {
+		for _, index := range []repository.TokenIndex{uc.refreshTokens, uc.accessTokens} {
+			tokens, err := index.Take(ctx, id)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for token := range tokens {
+				errs = append(errs, uc.cache.Delete(token))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
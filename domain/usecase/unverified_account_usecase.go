@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"auth-service/constants"
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	"github.com/anhvanhoa/service-core/domain/token"
+)
+
+// UnverifiedAccountPolicy: Retention <= 0 giữ tài khoản chưa xác thực mãi mãi,
+// ReminderLead > 0 thì gửi mail nhắc trước khi xóa và chỉ xóa tài khoản đã được nhắc đủ ReminderLead
+type UnverifiedAccountPolicy struct {
+	Retention    time.Duration
+	ReminderLead time.Duration
+	VerifyLink   string
+}
+
+type UnverifiedAccountUsecase interface {
+	SendReminders(ctx context.Context) (int, error)
+	Purge(ctx context.Context) (int, error)
+}
+
+type unverifiedAccountUsecaseImpl struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	outboxRepo  repository.OutboxRepository
+	tx          repository.ManagerTransaction
+	token       token.TokenAuthI
+	cache       cache.CacheI
+	policy      UnverifiedAccountPolicy
+}
+
+func NewUnverifiedAccountUsecase(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	outboxRepo repository.OutboxRepository,
+	tx repository.ManagerTransaction,
+	token token.TokenAuthI,
+	cache cache.CacheI,
+	policy UnverifiedAccountPolicy,
+) UnverifiedAccountUsecase {
+	return &unverifiedAccountUsecaseImpl{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		outboxRepo:  outboxRepo,
+		tx:          tx,
+		token:       token,
+		cache:       cache,
+		policy:      policy,
+	}
+}
+
+func (uc *unverifiedAccountUsecaseImpl) SendReminders(ctx context.Context) (int, error) {
+	if uc.policy.Retention <= 0 || uc.policy.ReminderLead <= 0 {
+		return 0, nil
+	}
+	registeredBefore := time.Now().Add(-max(uc.policy.Retention-uc.policy.ReminderLead, 0))
+	sent := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		users, err := uc.userRepo.ListUnverifiedToRemind(ctx, registeredBefore, janitorBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for _, user := range users {
+			if err := uc.remind(ctx, user); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+		if len(users) < janitorBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// remind cấp link xác thực mới còn hiệu lực tới lúc tài khoản bị xóa và gửi mail nhắc qua outbox
+func (uc *unverifiedAccountUsecaseImpl) remind(ctx context.Context, user entity.User) error {
+	deleteAt := time.Now().Add(uc.policy.ReminderLead)
+	if user.RegisteredAt != nil {
+		deleteAt = maxTime(deleteAt, user.RegisteredAt.Add(uc.policy.Retention))
+	}
+	var verifyToken string
+	err := uc.tx.RunInTransaction(func(ctx context.Context) error {
+		var err error
+		if err = uc.sessionRepo.Tx(ctx).DeleteSessionVerifyByUserID(ctx, user.ID); err != nil {
+			return err
+		}
+		if verifyToken, err = uc.token.GenAuthToken(user.ID, user.CodeVerify, deleteAt); err != nil {
+			return err
+		}
+		if err = uc.sessionRepo.Tx(ctx).CreateSession(entity.Session{
+			Token:     verifyToken,
+			UserID:    user.ID,
+			Type:      entity.SessionTypeVerify,
+			CreatedAt: time.Now(),
+			ExpiredAt: deleteAt,
+		}); err != nil {
+			return err
+		}
+		mail, err := entity.NewOutboxMail(constants.TPL_VERIFY_REMINDER_MAIL, entity.OutboxMail{
+			Tos: []string{user.Email},
+			Data: map[string]any{
+				"user":      user.GetInfor(),
+				"link":      uc.policy.VerifyLink + verifyToken,
+				"delete_at": deleteAt,
+			},
+			Message: "Send verify reminder to " + user.Email,
+		})
+		if err != nil {
+			return err
+		}
+		if err = uc.outboxRepo.Tx(ctx).CreateOutboxMessage(ctx, mail); err != nil {
+			return err
+		}
+		return uc.userRepo.Tx(ctx).MarkVerifyReminderSent(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+	uc.cache.Set(verifyToken, []byte(constants.TPL_VERIFY_MAIL), time.Until(deleteAt))
+	return nil
+}
+
+func (uc *unverifiedAccountUsecaseImpl) Purge(ctx context.Context) (int, error) {
+	if uc.policy.Retention <= 0 {
+		return 0, nil
+	}
+	now := time.Now()
+	filter := repository.UnverifiedFilter{
+		RegisteredBefore: now.Add(-uc.policy.Retention),
+	}
+	if uc.policy.ReminderLead > 0 {
+		remindedBefore := now.Add(-uc.policy.ReminderLead)
+		filter.RemindedBefore = &remindedBefore
+	}
+	return deleteInBatches(ctx, func(ctx context.Context) (int, error) {
+		return uc.userRepo.DeleteUnverified(ctx, filter, janitorBatchSize)
+	})
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -27,6 +27,9 @@ type verifyAccountUsecaseImpl struct {
 	sessionRepo repository.SessionRepository
 	token       token.TokenAuthI
 	cache       cache.CacheI
+	tx          repository.ManagerTransaction
+	publisher   repository.EventPublisher
+	workers     repository.WorkerGroup
 }
 
 func NewVerifyAccountUsecase(
@@ -34,12 +37,18 @@ func NewVerifyAccountUsecase(
 	sessionRepo repository.SessionRepository,
 	token token.TokenAuthI,
 	cache cache.CacheI,
+	tx repository.ManagerTransaction,
+	publisher repository.EventPublisher,
+	workers repository.WorkerGroup,
 ) VerifyAccountUsecase {
 	return &verifyAccountUsecaseImpl{
 		userRepo,
 		sessionRepo,
 		token,
 		cache,
+		tx,
+		publisher,
+		workers,
 	}
 }
 
@@ -49,10 +58,10 @@ func (u *verifyAccountUsecaseImpl) VerifyRegister(t string) (*token.AuthClaims,
 			return nil, ErrTokenNotFound
 		}
 	} else {
-		go func() {
-			u.sessionRepo.DeleteSessionAuthByToken(context.Background(), t)
+		u.workers.Go("delete_verify_session", func(ctx context.Context) error {
 			u.cache.Delete(t)
-		}()
+			return u.sessionRepo.DeleteSessionAuthByToken(ctx, t)
+		})
 	}
 
 	data, err := u.token.VerifyAuthToken(t)
@@ -77,6 +86,12 @@ func (u *verifyAccountUsecaseImpl) VerifyAccount(id string) error {
 		CodeVerify: "",
 		Veryfied:   &t,
 	}
-	_, err := u.userRepo.UpdateUser(id, user)
-	return err
+	return u.tx.RunInTransaction(func(ctx context.Context) error {
+		if _, err := u.userRepo.Tx(ctx).UpdateUser(id, user); err != nil {
+			return err
+		}
+		return u.publisher.Publish(ctx, entity.NewUserEvent(entity.UserEventVerified, id, entity.UserVerifiedData{
+			VerifiedAt: t,
+		}))
+	})
 }
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"time"
+)
+
+const (
+	webhookBatchSize   = 20
+	webhookSendTimeout = 10 * time.Second
+	// lease phải dài hơn thời gian gửi cả lô, nếu không replica khác claim lại delivery đang gửi dở và gửi trùng
+	webhookLease       = webhookBatchSize*webhookSendTimeout + time.Minute
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+)
+
+type WebhookDispatchUsecase interface {
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type webhookDispatchUsecaseImpl struct {
+	webhookRepo repository.WebhookRepository
+	sender      repository.WebhookSender
+}
+
+func NewWebhookDispatchUsecase(webhookRepo repository.WebhookRepository, sender repository.WebhookSender) WebhookDispatchUsecase {
+	return &webhookDispatchUsecaseImpl{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+	}
+}
+
+// Dispatch gửi một lô delivery đến hạn, trả về số delivery thành công
+func (uc *webhookDispatchUsecaseImpl) Dispatch(ctx context.Context) (int, error) {
+	deliveries, err := uc.webhookRepo.ClaimDueDeliveries(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+	subs := make(map[string]entity.WebhookSubscription)
+	delivered := 0
+	for _, d := range deliveries {
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			if sub, err = uc.webhookRepo.GetSubscriptionByID(ctx, d.SubscriptionID); err != nil {
+				return delivered, err
+			}
+			subs[d.SubscriptionID] = sub
+		}
+		attempts := d.Attempts + 1
+		if !sub.Active {
+			if err := uc.webhookRepo.MarkFailed(ctx, d.ID, d.Attempts, 0, "subscription is inactive"); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		code, sendErr := uc.send(ctx, sub, d)
+		if sendErr == nil {
+			if err := uc.webhookRepo.MarkDelivered(ctx, d.ID, attempts, code); err != nil {
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+		if attempts >= webhookMaxAttempts {
+			err = uc.webhookRepo.MarkFailed(ctx, d.ID, attempts, code, sendErr.Error())
+		} else {
+			backoff := webhookBaseBackoff << (attempts - 1)
+			if backoff > webhookMaxBackoff {
+				backoff = webhookMaxBackoff
+			}
+			err = uc.webhookRepo.MarkRetry(ctx, d.ID, attempts, code, time.Now().Add(backoff), sendErr.Error())
+		}
+		if err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+func (uc *webhookDispatchUsecaseImpl) send(ctx context.Context, sub entity.WebhookSubscription, d entity.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookSendTimeout)
+	defer cancel()
+	return uc.sender.Send(ctx, sub, d)
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"auth-service/infrastructure/webhook"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type markCall struct {
+	kind     string
+	id       string
+	attempts int
+	status   int
+	next     time.Time
+}
+
+// fakeWebhookRepo chỉ cài các hàm Dispatch dùng, gọi hàm khác sẽ panic
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+	subs       map[string]entity.WebhookSubscription
+	deliveries []entity.WebhookDelivery
+	lease      time.Duration
+	calls      []markCall
+}
+
+func (r *fakeWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	r.lease = lease
+	return r.deliveries, nil
+}
+
+func (r *fakeWebhookRepo) GetSubscriptionByID(ctx context.Context, id string) (entity.WebhookSubscription, error) {
+	return r.subs[id], nil
+}
+
+func (r *fakeWebhookRepo) MarkDelivered(ctx context.Context, id string, attempts, responseStatus int) error {
+	r.calls = append(r.calls, markCall{kind: "delivered", id: id, attempts: attempts, status: responseStatus})
+	return nil
+}
+
+func (r *fakeWebhookRepo) MarkRetry(ctx context.Context, id string, attempts, responseStatus int, next time.Time, lastError string) error {
+	r.calls = append(r.calls, markCall{kind: "retry", id: id, attempts: attempts, status: responseStatus, next: next})
+	return nil
+}
+
+func (r *fakeWebhookRepo) MarkFailed(ctx context.Context, id string, attempts, responseStatus int, lastError string) error {
+	r.calls = append(r.calls, markCall{kind: "failed", id: id, attempts: attempts, status: responseStatus})
+	return nil
+}
+
+func newWebhookServer(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func dispatch(t *testing.T, srv *httptest.Server, sub entity.WebhookSubscription, d entity.WebhookDelivery) (*fakeWebhookRepo, int) {
+	t.Helper()
+	sub.ID = "sub-1"
+	sub.URL = srv.URL
+	d.SubscriptionID = sub.ID
+	repo := &fakeWebhookRepo{
+		subs:       map[string]entity.WebhookSubscription{sub.ID: sub},
+		deliveries: []entity.WebhookDelivery{d},
+	}
+	delivered, err := NewWebhookDispatchUsecase(repo, webhook.NewSender(srv.Client())).Dispatch(context.Background())
+	if err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if len(repo.calls) != 1 {
+		t.Fatalf("calls = %+v, want exactly one", repo.calls)
+	}
+	return repo, delivered
+}
+
+func TestWebhookDispatch(t *testing.T) {
+	active := entity.WebhookSubscription{Active: true, Secret: "secret"}
+	tests := []struct {
+		name         string
+		status       int
+		sub          entity.WebhookSubscription
+		attempts     int
+		wantKind     string
+		wantAttempts int
+		wantBackoff  time.Duration
+		wantHits     int32
+	}{
+		{name: "delivered", status: http.StatusOK, sub: active, wantKind: "delivered", wantAttempts: 1, wantHits: 1},
+		{name: "first failure retries", status: http.StatusServiceUnavailable, sub: active, wantKind: "retry", wantAttempts: 1, wantBackoff: webhookBaseBackoff, wantHits: 1},
+		{name: "backoff doubles", status: http.StatusInternalServerError, sub: active, attempts: 3, wantKind: "retry", wantAttempts: 4, wantBackoff: 8 * webhookBaseBackoff, wantHits: 1},
+		{name: "last attempt dead-letters", status: http.StatusBadGateway, sub: active, attempts: webhookMaxAttempts - 1, wantKind: "failed", wantAttempts: webhookMaxAttempts, wantHits: 1},
+		{name: "inactive subscription dead-letters without sending", status: http.StatusOK, sub: entity.WebhookSubscription{}, attempts: 2, wantKind: "failed", wantAttempts: 2, wantHits: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := newWebhookServer(t, tt.status)
+			start := time.Now()
+			repo, delivered := dispatch(t, srv, tt.sub, entity.WebhookDelivery{ID: "d-1", Attempts: tt.attempts, Payload: []byte(`{}`)})
+
+			call := repo.calls[0]
+			if call.kind != tt.wantKind || call.id != "d-1" || call.attempts != tt.wantAttempts {
+				t.Fatalf("call = %+v, want %s d-1 attempts=%d", call, tt.wantKind, tt.wantAttempts)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Fatalf("requests = %d, want %d", got, tt.wantHits)
+			}
+			if tt.wantHits > 0 && call.status != tt.status {
+				t.Fatalf("response status = %d, want %d", call.status, tt.status)
+			}
+			if (delivered == 1) != (tt.wantKind == "delivered") {
+				t.Fatalf("delivered = %d for %s", delivered, tt.wantKind)
+			}
+			if tt.wantBackoff > 0 {
+				if next := call.next.Sub(start); next < tt.wantBackoff || next > tt.wantBackoff+5*time.Second {
+					t.Fatalf("next attempt in %s, want %s", next, tt.wantBackoff)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookLeaseCoversBatch(t *testing.T) {
+	srv, _ := newWebhookServer(t, http.StatusOK)
+	repo, _ := dispatch(t, srv, entity.WebhookSubscription{Active: true}, entity.WebhookDelivery{ID: "d-1"})
+	if repo.lease < webhookBatchSize*webhookSendTimeout {
+		t.Fatalf("lease %s is shorter than a batch of %d sends with %s timeout", repo.lease, webhookBatchSize, webhookSendTimeout)
+	}
+}
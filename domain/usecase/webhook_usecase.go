@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+
+	"github.com/anhvanhoa/service-core/domain/oops"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWebhookPageSize = 20
+	maxWebhookPageSize     = 100
+	webhookSecretLength    = 32
+)
+
+var (
+	ErrInvalidWebhookURL       = oops.New("URL webhook không hợp lệ")
+	ErrInvalidWebhookEvent     = oops.New("Loại sự kiện webhook không hợp lệ")
+	ErrWebhookDeliveryNotFound = oops.New("Không tìm thấy lượt gửi webhook")
+)
+
+var webhookEventTypes = []entity.UserEventType{
+	entity.UserEventRegistered,
+	entity.UserEventVerified,
+	entity.UserEventPasswordChanged,
+	entity.UserEventSuspended,
+	entity.UserEventDeleted,
+}
+
+type CreateWebhookReq struct {
+	URL        string
+	Secret     string
+	EventTypes []string
+}
+
+type WebhookUsecase interface {
+	CreateSubscription(ctx context.Context, req CreateWebhookReq) (entity.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int, error)
+	Replay(ctx context.Context, deliveryID string) error
+}
+
+type webhookUsecaseImpl struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookUsecase(webhookRepo repository.WebhookRepository) WebhookUsecase {
+	return &webhookUsecaseImpl{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// CreateSubscription tạo secret ngẫu nhiên nếu không truyền vào, secret chỉ được trả về lúc tạo
+func (uc *webhookUsecaseImpl) CreateSubscription(ctx context.Context, req CreateWebhookReq) (entity.WebhookSubscription, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return entity.WebhookSubscription{}, ErrInvalidWebhookURL
+	}
+	if len(req.EventTypes) == 0 {
+		return entity.WebhookSubscription{}, ErrInvalidWebhookEvent
+	}
+	for _, t := range req.EventTypes {
+		if t != "*" && !slices.Contains(webhookEventTypes, entity.UserEventType(t)) {
+			return entity.WebhookSubscription{}, ErrInvalidWebhookEvent
+		}
+	}
+	if req.Secret == "" {
+		b := make([]byte, webhookSecretLength)
+		if _, err := rand.Read(b); err != nil {
+			return entity.WebhookSubscription{}, err
+		}
+		req.Secret = hex.EncodeToString(b)
+	}
+	sub := entity.WebhookSubscription{
+		ID:         uuid.NewString(),
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+	}
+	if err := uc.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return entity.WebhookSubscription{}, err
+	}
+	return sub, nil
+}
+
+func (uc *webhookUsecaseImpl) ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	return uc.webhookRepo.ListSubscriptions(ctx)
+}
+
+func (uc *webhookUsecaseImpl) DeleteSubscription(ctx context.Context, id string) error {
+	return uc.webhookRepo.DeleteSubscription(ctx, id)
+}
+
+func (uc *webhookUsecaseImpl) ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultWebhookPageSize
+	}
+	if filter.PageSize > maxWebhookPageSize {
+		filter.PageSize = maxWebhookPageSize
+	}
+	return uc.webhookRepo.ListDeliveries(ctx, filter)
+}
+
+// Replay đưa delivery (kể cả đã gửi hoặc dead-letter) về hàng đợi với số lần thử mới
+func (uc *webhookUsecaseImpl) Replay(ctx context.Context, deliveryID string) error {
+	ok, err := uc.webhookRepo.ReplayDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
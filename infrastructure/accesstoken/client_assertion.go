@@ -0,0 +1,113 @@
+package accesstoken
+
+import (
+	"auth-service/domain/repository"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// maxAssertionLifetime giới hạn exp của assertion để assertion bị lộ không dùng được lâu
+const maxAssertionLifetime = 5 * time.Minute
+
+var (
+	ErrInvalidPublicKey      = errors.New("public key must be a PEM encoded RSA, ECDSA or Ed25519 key")
+	ErrAssertionMissingKeyID = errors.New("client assertion has no kid header")
+	ErrAssertionTooLong      = errors.New("client assertion lifetime is too long")
+	ErrAssertionMissingID    = errors.New("client assertion has no jti claim")
+)
+
+type clientAssertionVerifier struct {
+	audience string
+}
+
+// NewClientAssertionVerifier xác thực JWT assertion (RFC 7523) do service account ký bằng private key,
+// audience là tên service nhận assertion
+func NewClientAssertionVerifier(audience string) repository.ClientAssertionVerifier {
+	return &clientAssertionVerifier{
+		audience: audience,
+	}
+}
+
+func (v *clientAssertionVerifier) ValidatePublicKey(publicKeyPEM string) error {
+	_, err := parsePublicKey(publicKeyPEM)
+	return err
+}
+
+func (v *clientAssertionVerifier) KeyID(assertion string) (string, error) {
+	t, _, err := jwt.NewParser().ParseUnverified(assertion, jwt.MapClaims{})
+	if err != nil {
+		return "", err
+	}
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return "", ErrAssertionMissingKeyID
+	}
+	return kid, nil
+}
+
+func (v *clientAssertionVerifier) Verify(assertion, keyID, publicKeyPEM string) (string, time.Time, error) {
+	key, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	claims := jwt.RegisteredClaims{}
+	_, err = jwt.ParseWithClaims(assertion, &claims, func(t *jwt.Token) (any, error) {
+		switch key.(type) {
+		case *rsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodRSA); ok {
+				return key, nil
+			}
+			if _, ok := t.Method.(*jwt.SigningMethodRSAPSS); ok {
+				return key, nil
+			}
+		case *ecdsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodECDSA); ok {
+				return key, nil
+			}
+		case ed25519.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodEd25519); ok {
+				return key, nil
+			}
+		}
+		return nil, ErrUnexpectedSigningMethod
+	},
+		jwt.WithAudience(v.audience),
+		jwt.WithIssuer(keyID),
+		jwt.WithSubject(keyID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	exp := claims.ExpiresAt.Time
+	if time.Until(exp) > maxAssertionLifetime {
+		return "", time.Time{}, ErrAssertionTooLong
+	}
+	if claims.ID == "" {
+		return "", time.Time{}, ErrAssertionMissingID
+	}
+	return claims.ID, exp, nil
+}
+
+func parsePublicKey(publicKeyPEM string) (any, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	}
+	return nil, ErrInvalidPublicKey
+}
@@ -0,0 +1,85 @@
+package accesstoken
+
+import (
+	"auth-service/domain/repository"
+	"errors"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/token"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// ClaimOrganization là claim chứa id tổ chức đang hoạt động trong access token
+	ClaimOrganization = "org"
+	// ClaimServiceAccount chỉ có trong token của service account, giá trị là id service account
+	ClaimServiceAccount = "sa"
+)
+
+var ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
+
+type issuer struct {
+	token.TokenAuthorizeI
+	secret []byte
+}
+
+func NewIssuer(secret string) repository.AccessTokenIssuer {
+	return &issuer{
+		TokenAuthorizeI: token.NewToken(secret),
+		secret:          []byte(secret),
+	}
+}
+
+func (i *issuer) GenAuthorizeTokenWithOrganization(id, fullName, email, organizationID string, exp time.Time) (string, error) {
+	signed, err := i.GenAuthorizeToken(id, fullName, email, exp)
+	if err != nil || organizationID == "" {
+		return signed, err
+	}
+	return i.resign(signed, map[string]any{ClaimOrganization: organizationID})
+}
+
+func (i *issuer) GenServiceAccountToken(id, name, email, organizationID string, exp time.Time) (string, error) {
+	signed, err := i.GenAuthorizeToken(id, name, email, exp)
+	if err != nil {
+		return "", err
+	}
+	extra := map[string]any{ClaimServiceAccount: id}
+	if organizationID != "" {
+		extra[ClaimOrganization] = organizationID
+	}
+	return i.resign(signed, extra)
+}
+
+func (i *issuer) ServiceAccountOf(accessToken string) (string, time.Time, bool) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(accessToken, claims, i.keyFunc); err != nil {
+		return "", time.Time{}, false
+	}
+	id, _ := claims[ClaimServiceAccount].(string)
+	exp, err := claims.GetExpirationTime()
+	if id == "" || err != nil || exp == nil {
+		return "", time.Time{}, false
+	}
+	return id, exp.Time, true
+}
+
+// resign thêm claim vào token do service-core ký rồi ký lại cùng thuật toán,
+// các claim còn lại giữ nguyên nên service khác dùng service-core vẫn xác thực được token
+func (i *issuer) resign(signed string, extra map[string]any) (string, error) {
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(signed, claims, i.keyFunc)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return jwt.NewWithClaims(parsed.Method, claims).SignedString(i.secret)
+}
+
+func (i *issuer) keyFunc(t *jwt.Token) (any, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, ErrUnexpectedSigningMethod
+	}
+	return i.secret, nil
+}
@@ -0,0 +1,66 @@
+package event
+
+import (
+	"auth-service/constants"
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	publishAttempts = 3
+	publishBackoff  = 200 * time.Millisecond
+	// Số lần asynq giao lại cho consumer trước khi chuyển sang archived
+	consumerMaxRetry = 25
+	// Giữ task đã xử lý để TaskID còn chống trùng trong khoảng thời gian này
+	taskRetention = 24 * time.Hour
+)
+
+type asynqPublisher struct {
+	client *asynq.Client
+	log    *log.LogGRPCImpl
+}
+
+func NewAsynqPublisher(client *asynq.Client, log *log.LogGRPCImpl) repository.EventPublisher {
+	return &asynqPublisher{
+		client: client,
+		log:    log,
+	}
+}
+
+// Publish đẩy sự kiện vào queue event với task type chính là loại sự kiện.
+// Sự kiện có thể được giao nhiều lần, consumer phải idempotent theo event.ID.
+func (p *asynqPublisher) Publish(ctx context.Context, event entity.UserEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(string(event.Type), payload)
+	for attempt := 1; ; attempt++ {
+		_, err = p.client.EnqueueContext(ctx, task,
+			asynq.Queue(string(constants.QUEUE_EVENT)),
+			asynq.TaskID(event.ID),
+			asynq.MaxRetry(consumerMaxRetry),
+			asynq.Retention(taskRetention),
+		)
+		if err == nil || errors.Is(err, asynq.ErrTaskIDConflict) {
+			return nil
+		}
+		if attempt >= publishAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(publishBackoff * time.Duration(attempt)):
+		}
+	}
+	p.log.Error("Failed to publish " + string(event.Type) + " event " + event.ID + ": " + err.Error())
+	return err
+}
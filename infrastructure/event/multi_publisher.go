@@ -0,0 +1,29 @@
+package event
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+)
+
+type multiPublisher struct {
+	publishers []repository.EventPublisher
+}
+
+// NewMultiPublisher gửi mỗi sự kiện tới tất cả publisher, lỗi của một publisher không chặn các publisher khác
+func NewMultiPublisher(publishers ...repository.EventPublisher) repository.EventPublisher {
+	return &multiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, event entity.UserEvent) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,27 @@
+package event
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+)
+
+type outboxPublisher struct {
+	outboxRepo repository.OutboxRepository
+}
+
+// NewOutboxPublisher ghi sự kiện vào bảng outbox thay vì gửi trực tiếp.
+// Nếu ctx mang transaction thì sự kiện được commit cùng thay đổi dữ liệu, relay sẽ gửi sau.
+func NewOutboxPublisher(outboxRepo repository.OutboxRepository) repository.EventPublisher {
+	return &outboxPublisher{
+		outboxRepo: outboxRepo,
+	}
+}
+
+func (p *outboxPublisher) Publish(ctx context.Context, event entity.UserEvent) error {
+	msg, err := entity.NewOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+	return p.outboxRepo.Tx(ctx).CreateOutboxMessage(ctx, msg)
+}
@@ -0,0 +1,48 @@
+package event
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+type webhookPublisher struct {
+	webhookRepo repository.WebhookRepository
+}
+
+// NewWebhookPublisher tạo delivery cho mỗi subscription đang bật nhận loại sự kiện,
+// việc gửi HTTP do WebhookDispatchJob đảm nhận.
+func NewWebhookPublisher(webhookRepo repository.WebhookRepository) repository.EventPublisher {
+	return &webhookPublisher{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, event entity.UserEvent) error {
+	subs, err := p.webhookRepo.GetActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var deliveries []entity.WebhookDelivery
+	for _, sub := range subs {
+		if !sub.Accepts(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, entity.WebhookDelivery{
+			ID:             uuid.NewString(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         entity.WebhookPending,
+		})
+	}
+	return p.webhookRepo.CreateDeliveries(ctx, deliveries)
+}
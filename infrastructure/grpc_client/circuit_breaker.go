@@ -0,0 +1,89 @@
+package grpc_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker mở mạch sau threshold lỗi liên tiếp, sau cooldown cho một request thử (half-open)
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     circuitState
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (cb *circuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return ErrCircuitOpen
+		}
+		cb.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		// Chỉ cho một request thử đi qua khi half-open
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// Done ghi nhận kết quả của request. Chỉ Unavailable và DeadlineExceeded được tính là lỗi;
+// lỗi nghiệp vụ (NotFound, InvalidArgument...) cho thấy service vẫn trả lời nên tính là thành công,
+// request bị người gọi hủy không nói lên gì về service nên không được tính.
+func (cb *circuitBreaker) Done(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch errorCode(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+	case codes.Canceled:
+		// Trả lượt thử half-open để request sau được thử lại ngay, openedAt đã quá cooldown
+		if cb.state == circuitHalfOpen {
+			cb.state = circuitOpen
+		}
+		return
+	default:
+		cb.state = circuitClosed
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// errorCode trả về mã gRPC của lỗi, kể cả lỗi context chưa được bọc thành status
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	}
+	return status.Code(err)
+}
@@ -0,0 +1,91 @@
+package grpc_client
+
+import (
+	"auth-service/constants"
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/queue"
+	proto_mail_history "github.com/anhvanhoa/sf-proto/gen/mail_history/v1"
+	proto_mail_template "github.com/anhvanhoa/sf-proto/gen/mail_tmpl/v1"
+	proto_status_history "github.com/anhvanhoa/sf-proto/gen/status_history/v1"
+	"github.com/hibiken/asynq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrMailServiceUnavailable = errors.New("mail service is not available")
+
+// Giữ task mail đã xử lý để TaskID còn chống trùng trong khoảng thời gian này
+const mailTaskRetention = 24 * time.Hour
+
+type mailSender struct {
+	mailService *MailService
+	client      *asynq.Client
+}
+
+func NewMailSender(mailService *MailService, client *asynq.Client) repository.MailSender {
+	return &mailSender{
+		mailService: mailService,
+		client:      client,
+	}
+}
+
+// Enqueue đẩy mail vào queue mail với task id là id truyền vào, enqueue lại cùng id bị asynq từ chối
+// nên relay chạy lại sau khi lỗi không gửi mail hai lần
+func (s *mailSender) Enqueue(ctx context.Context, id, templateID string, mail entity.OutboxMail) (string, error) {
+	payload := queue.NewPayloadMail(mail.Data, mail.Tos, templateID)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.client.EnqueueContext(ctx, asynq.NewTask(payload.GetType(), data),
+		asynq.Queue(string(constants.QUEUE_MAIL)),
+		asynq.TaskID(id),
+		asynq.Retention(mailTaskRetention),
+	)
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *mailSender) CreateHistory(ctx context.Context, taskID, templateID string, mail entity.OutboxMail) error {
+	if s.mailService == nil || s.mailService.Mtc == nil || s.mailService.Mhc == nil || s.mailService.Shc == nil {
+		return ErrMailServiceUnavailable
+	}
+	tmpl, err := s.mailService.Mtc.GetMailTmpl(ctx, &proto_mail_template.GetMailTmplRequest{
+		Id: templateID,
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(mail.Data)
+	if err != nil {
+		return err
+	}
+	if _, err := s.mailService.Mhc.CreateMailHistory(ctx, &proto_mail_history.CreateMailHistoryRequest{
+		Id:            taskID,
+		TemplateId:    tmpl.MailTmpl.Id,
+		Subject:       tmpl.MailTmpl.Subject,
+		Body:          tmpl.MailTmpl.Body,
+		Tos:           mail.Tos,
+		Data:          string(data),
+		EmailProvider: tmpl.MailTmpl.ProviderEmail,
+	}); err != nil && status.Code(err) != codes.AlreadyExists {
+		return err
+	}
+	if _, err := s.mailService.Shc.CreateStatusHistory(ctx, &proto_status_history.CreateStatusHistoryRequest{
+		MailHistoryId: taskID,
+		Status:        "pending",
+		Message:       mail.Message,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+	}); err != nil && status.Code(err) != codes.AlreadyExists {
+		return err
+	}
+	return nil
+}
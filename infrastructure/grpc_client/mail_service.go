@@ -1,6 +1,8 @@
 package grpc_client
 
 import (
+	"auth-service/infrastructure/tracing"
+
 	"github.com/anhvanhoa/service-core/domain/grpc_client"
 	proto_mail_history "github.com/anhvanhoa/sf-proto/gen/mail_history/v1"
 	proto_mail_provider "github.com/anhvanhoa/sf-proto/gen/mail_provider/v1"
@@ -19,10 +21,11 @@ func NewMailService(client *grpc_client.Client) *MailService {
 	if client == nil {
 		return &MailService{}
 	}
+	conn := tracing.WrapClientConn(client.GetConnection())
 	return &MailService{
-		Shc: proto_status_history.NewStatusHistoryServiceClient(client.GetConnection()),
-		Mtc: proto_mail_template.NewMailTmplServiceClient(client.GetConnection()),
-		Mpc: proto_mail_provider.NewMailProviderServiceClient(client.GetConnection()),
-		Mhc: proto_mail_history.NewMailHistoryServiceClient(client.GetConnection()),
+		Shc: proto_status_history.NewStatusHistoryServiceClient(conn),
+		Mtc: proto_mail_template.NewMailTmplServiceClient(conn),
+		Mpc: proto_mail_provider.NewMailProviderServiceClient(conn),
+		Mhc: proto_mail_history.NewMailHistoryServiceClient(conn),
 	}
 }
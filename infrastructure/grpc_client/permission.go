@@ -1,6 +1,8 @@
 package grpc_client
 
 import (
+	"auth-service/infrastructure/tracing"
+
 	gc "github.com/anhvanhoa/service-core/domain/grpc_client"
 	"github.com/anhvanhoa/service-core/domain/oops"
 	proto_permission "github.com/anhvanhoa/sf-proto/gen/permission/v1"
@@ -25,9 +27,10 @@ func NewPermissionClient(client *gc.Client) (PermissionClient, error) {
 	if client == nil {
 		return nil, ErrPermissionClientNotAvailable
 	}
+	conn := tracing.WrapClientConn(client.GetConnection())
 	return &PermissionClientImpl{
-		UserRoleServiceClient:   proto_user_role.NewUserRoleServiceClient(client.GetConnection()),
-		PermissionServiceClient: proto_permission.NewPermissionServiceClient(client.GetConnection()),
+		UserRoleServiceClient:   proto_user_role.NewUserRoleServiceClient(conn),
+		PermissionServiceClient: proto_permission.NewPermissionServiceClient(conn),
 	}, nil
 }
 
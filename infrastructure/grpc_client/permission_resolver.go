@@ -0,0 +1,180 @@
+package grpc_client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	proto_user_role "github.com/anhvanhoa/sf-proto/gen/user_role/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type FailPolicy string
+
+const (
+	// FailOpen cho request đi tiếp với quyền rỗng khi không lấy được quyền và không có snapshot
+	FailOpen FailPolicy = "fail_open"
+	// FailClosed từ chối request khi không lấy được quyền và không có snapshot
+	FailClosed FailPolicy = "fail_closed"
+)
+
+// Tên RPC dùng để cấu hình permission_policy
+const (
+	PermissionRPCLogin               = "login"
+	PermissionRPCRefreshToken        = "refresh_token"
+	PermissionRPCPersonalAccessToken = "personal_access_token"
+	PermissionRPCServiceAccount      = "service_account"
+)
+
+const (
+	permissionSnapshotPrefix = "permission_snapshot:"
+	permissionSnapshotTTL    = 7 * 24 * time.Hour
+	permissionCallTimeout    = 3 * time.Second
+	breakerThreshold         = 5
+	breakerCooldown          = 30 * time.Second
+)
+
+var ErrPermissionUnavailable = errors.New("permission service unavailable")
+
+type PermissionResult struct {
+	Permissions *proto_user_role.GetUserPermissionsResponse
+	// Stale true khi quyền lấy từ snapshot hoặc rỗng do fail-open
+	Stale bool
+}
+
+type PermissionResolver interface {
+	Resolve(ctx context.Context, rpc string, userID string) (PermissionResult, error)
+	Forget(userID string) error
+}
+
+// PermissionDialer tạo client tới permission service
+type PermissionDialer func() (PermissionClient, error)
+
+type permissionResolverImpl struct {
+	dial     PermissionDialer
+	mu       sync.Mutex
+	client   PermissionClient
+	cache    cache.CacheI
+	breaker  *circuitBreaker
+	policies map[string]FailPolicy
+}
+
+// NewPermissionResolver tạo client bằng dial ở lần gọi đầu tiên thay vì giữ client lúc khởi động.
+// Dial lỗi được tính là lỗi Unavailable của breaker và client bị bỏ sau lỗi Unavailable,
+// nên lần thử half-open sau cooldown cũng là lần kết nối lại.
+func NewPermissionResolver(dial PermissionDialer, cache cache.CacheI, policies map[string]string) PermissionResolver {
+	p := make(map[string]FailPolicy, len(policies))
+	for rpc, policy := range policies {
+		p[strings.ToLower(rpc)] = FailPolicy(strings.ToLower(policy))
+	}
+	return &permissionResolverImpl{
+		dial:     dial,
+		cache:    cache,
+		breaker:  newCircuitBreaker(breakerThreshold, breakerCooldown),
+		policies: p,
+	}
+}
+
+// Resolve lấy quyền từ permission service, lưu snapshot khi thành công.
+// Khi service lỗi hoặc mạch đang mở thì dùng snapshot gần nhất, không có snapshot thì áp dụng policy của RPC.
+func (r *permissionResolverImpl) Resolve(ctx context.Context, rpc string, userID string) (PermissionResult, error) {
+	permissions, err := r.fetch(ctx, userID)
+	if err == nil {
+		r.saveSnapshot(userID, permissions)
+		return PermissionResult{Permissions: permissions}, nil
+	}
+	if snapshot, ok := r.loadSnapshot(userID); ok {
+		return PermissionResult{Permissions: snapshot, Stale: true}, nil
+	}
+	if r.policy(rpc) == FailOpen {
+		return PermissionResult{
+			Permissions: &proto_user_role.GetUserPermissionsResponse{UserId: userID},
+			Stale:       true,
+		}, nil
+	}
+	return PermissionResult{}, errors.Join(ErrPermissionUnavailable, err)
+}
+
+// Forget xóa snapshot để quyền cũ không được dùng lại khi permission service lỗi
+func (r *permissionResolverImpl) Forget(userID string) error {
+	return r.cache.Delete(permissionSnapshotPrefix + userID)
+}
+
+func (r *permissionResolverImpl) fetch(ctx context.Context, userID string) (*proto_user_role.GetUserPermissionsResponse, error) {
+	if err := r.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	client, err := r.connect()
+	if err != nil {
+		r.breaker.Done(status.Error(codes.Unavailable, err.Error()))
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, permissionCallTimeout)
+	defer cancel()
+	res, err := client.UserRoleService().GetUserPermissions(ctx, &proto_user_role.GetUserPermissionsRequest{
+		UserId: userID,
+	})
+	r.breaker.Done(err)
+	if errorCode(err) == codes.Unavailable {
+		r.disconnect(client)
+	}
+	return res, err
+}
+
+func (r *permissionResolverImpl) connect() (PermissionClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client != nil {
+		return r.client, nil
+	}
+	client, err := r.dial()
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || client.UserRoleService() == nil {
+		return nil, ErrPermissionClientNotAvailable
+	}
+	r.client = client
+	return client, nil
+}
+
+// disconnect bỏ client lỗi để lần gọi sau dial lại, client đã được thay bởi lần dial khác thì giữ nguyên
+func (r *permissionResolverImpl) disconnect(client PermissionClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == client {
+		r.client = nil
+	}
+}
+
+func (r *permissionResolverImpl) policy(rpc string) FailPolicy {
+	if p, ok := r.policies[rpc]; ok && p == FailOpen {
+		return FailOpen
+	}
+	return FailClosed
+}
+
+func (r *permissionResolverImpl) saveSnapshot(userID string, permissions *proto_user_role.GetUserPermissionsResponse) {
+	data, err := proto.Marshal(permissions)
+	if err != nil {
+		return
+	}
+	r.cache.Set(permissionSnapshotPrefix+userID, data, permissionSnapshotTTL)
+}
+
+func (r *permissionResolverImpl) loadSnapshot(userID string) (*proto_user_role.GetUserPermissionsResponse, bool) {
+	data, err := r.cache.Get(permissionSnapshotPrefix + userID)
+	if err != nil || data == nil {
+		return nil, false
+	}
+	permissions := &proto_user_role.GetUserPermissionsResponse{}
+	if err := proto.Unmarshal(data, permissions); err != nil {
+		return nil, false
+	}
+	return permissions, true
+}
@@ -0,0 +1,115 @@
+package grpc_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	proto_permission "github.com/anhvanhoa/sf-proto/gen/permission/v1"
+	proto_user_role "github.com/anhvanhoa/sf-proto/gen/user_role/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeUserRoleClient struct {
+	proto_user_role.UserRoleServiceClient
+	err error
+}
+
+func (c *fakeUserRoleClient) GetUserPermissions(_ context.Context, in *proto_user_role.GetUserPermissionsRequest, _ ...grpc.CallOption) (*proto_user_role.GetUserPermissionsResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &proto_user_role.GetUserPermissionsResponse{UserId: in.UserId}, nil
+}
+
+type fakePermissionClient struct {
+	userRole *fakeUserRoleClient
+}
+
+func (c *fakePermissionClient) PermissionService() proto_permission.PermissionServiceClient {
+	return nil
+}
+
+func (c *fakePermissionClient) UserRoleService() proto_user_role.UserRoleServiceClient {
+	return c.userRole
+}
+
+// fakeDialer trả lần lượt các kết quả dial đã định sẵn và đếm số lần dial
+type fakeDialer struct {
+	results []func() (PermissionClient, error)
+	calls   int
+}
+
+func (d *fakeDialer) dial() (PermissionClient, error) {
+	result := d.results[min(d.calls, len(d.results)-1)]
+	d.calls++
+	return result()
+}
+
+func newTestResolver(dial PermissionDialer, cooldown time.Duration) *permissionResolverImpl {
+	return &permissionResolverImpl{
+		dial:    dial,
+		breaker: newCircuitBreaker(1, cooldown),
+	}
+}
+
+func TestPermissionResolverDialsLazily(t *testing.T) {
+	down := func() (PermissionClient, error) { return nil, errors.New("connection refused") }
+	up := func() (PermissionClient, error) {
+		return &fakePermissionClient{userRole: &fakeUserRoleClient{}}, nil
+	}
+	d := &fakeDialer{results: []func() (PermissionClient, error){down, up}}
+	cooldown := 20 * time.Millisecond
+	r := newTestResolver(d.dial, cooldown)
+	if d.calls != 0 {
+		t.Fatalf("dialed %d times at construction, want 0", d.calls)
+	}
+
+	if _, err := r.fetch(context.Background(), "u1"); err == nil {
+		t.Fatal("fetch succeeded while the service was down")
+	}
+	// dial lỗi mở mạch, lần gọi trong cooldown không dial lại
+	if _, err := r.fetch(context.Background(), "u1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("fetch during cooldown = %v, want %v", err, ErrCircuitOpen)
+	}
+	if d.calls != 1 {
+		t.Fatalf("dialed %d times, want 1", d.calls)
+	}
+
+	time.Sleep(cooldown)
+	res, err := r.fetch(context.Background(), "u1")
+	if err != nil || res.GetUserId() != "u1" {
+		t.Fatalf("half-open fetch = %v, %v; want a reconnect", res, err)
+	}
+	if _, err := r.fetch(context.Background(), "u1"); err != nil {
+		t.Fatalf("fetch after reconnect: %v", err)
+	}
+	if d.calls != 2 {
+		t.Fatalf("dialed %d times, want the client to be reused after reconnecting", d.calls)
+	}
+}
+
+func TestPermissionResolverRedialsAfterUnavailable(t *testing.T) {
+	broken := &fakePermissionClient{userRole: &fakeUserRoleClient{err: status.Error(codes.Unavailable, "down")}}
+	healthy := &fakePermissionClient{userRole: &fakeUserRoleClient{}}
+	d := &fakeDialer{results: []func() (PermissionClient, error){
+		func() (PermissionClient, error) { return broken, nil },
+		func() (PermissionClient, error) { return healthy, nil },
+	}}
+	cooldown := 20 * time.Millisecond
+	r := newTestResolver(d.dial, cooldown)
+
+	if _, err := r.fetch(context.Background(), "u1"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("fetch = %v, want Unavailable", err)
+	}
+	time.Sleep(cooldown)
+	if _, err := r.fetch(context.Background(), "u1"); err != nil {
+		t.Fatalf("half-open fetch: %v", err)
+	}
+	if d.calls != 2 {
+		t.Fatalf("dialed %d times, want a new client after Unavailable", d.calls)
+	}
+}
@@ -0,0 +1,41 @@
+package grpcservice
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/infrastructure/metrics"
+	"context"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+func (a *authService) newAuthEvent(ctx context.Context, eventType entity.AuthEventType, os string) *entity.AuthEvent {
+	ip, userAgent := a.getClientInfo(ctx)
+	return &entity.AuthEvent{
+		Type:      eventType,
+		IP:        ip,
+		UserAgent: userAgent,
+		Os:        os,
+	}
+}
+
+// recordAuthEvent được gọi trong defer của mỗi handler, outcome dựa trên lỗi trả về.
+func (a *authService) recordAuthEvent(event *entity.AuthEvent, err error) {
+	event.Outcome = entity.AuthEventSuccess
+	reason := event.Reason
+	if err != nil {
+		event.Outcome = entity.AuthEventFailure
+		event.Reason = status.Convert(err).Message()
+		reason = status.Code(err).String()
+	}
+	metrics.ObserveAuthEvent(string(event.Type), string(event.Outcome), reason)
+	event.CreatedAt = time.Now()
+	e := *event
+	a.workers.Go("record_auth_event", func(ctx context.Context) error {
+		return a.authEventUc.Record(ctx, e)
+	})
+}
+
+func (a *authService) getClientInfo(ctx context.Context) (string, string) {
+	return a.clientInfo.ClientInfo(ctx)
+}
@@ -2,13 +2,19 @@ package grpcservice
 
 import (
 	"auth-service/bootstrap"
+	"auth-service/domain/repository"
 	"auth-service/domain/usecase"
+	"auth-service/infrastructure/accesstoken"
+	"auth-service/infrastructure/event"
 	"auth-service/infrastructure/grpc_client"
+	"auth-service/infrastructure/hasher"
+	"auth-service/infrastructure/health"
+	"auth-service/infrastructure/metrics"
 	"auth-service/infrastructure/repo"
+	"time"
 
 	"github.com/anhvanhoa/service-core/domain/cache"
 	"github.com/anhvanhoa/service-core/domain/goid"
-	hashpass "github.com/anhvanhoa/service-core/domain/hash_pass"
 	"github.com/anhvanhoa/service-core/domain/log"
 	"github.com/anhvanhoa/service-core/domain/queue"
 	"github.com/anhvanhoa/service-core/domain/saga"
@@ -25,7 +31,7 @@ type authService struct {
 	log              *log.LogGRPCImpl
 	uuid             goid.GoUUID
 	cache            cache.CacheI
-	permissionClient grpc_client.PermissionClient
+	permissionCache  *PermissionCache
 	mailService      *grpc_client.MailService
 	checkTokenUc     usecase.CheckTokenUsecase
 	loginUc          usecase.LoginUsecase
@@ -38,6 +44,14 @@ type authService struct {
 	resetTokenUc     usecase.ResetPasswordByTokenUsecase
 	checkCodeUc      usecase.CheckCodeUsecase
 	profileUc        usecase.ProfileUsecase
+	passwordPolicyUc usecase.PasswordPolicyUsecase
+	authEventUc      usecase.AuthEventUsecase
+	deviceUc         usecase.DeviceRecognitionUsecase
+	notMeUc          usecase.NotMeUsecase
+	tokens           *tokenExtractor
+	clientInfo       *clientInfoExtractor
+	healthChecker    *health.Checker
+	workers          repository.WorkerGroup
 }
 
 func NewAuthService(
@@ -45,28 +59,49 @@ func NewAuthService(
 	env *bootstrap.Env,
 	log *log.LogGRPCImpl,
 	mailService *grpc_client.MailService,
-	permissionClient grpc_client.PermissionClient,
+	permissionCache *PermissionCache,
 	queueClient queue.QueueClient,
 	cache cache.CacheI,
+	healthChecker *health.Checker,
+	workers repository.WorkerGroup,
+	sessionRepo repository.SessionRepository,
+	refreshTokens repository.TokenIndex,
+	revocationUc usecase.TokenRevocationUsecase,
+	notMeUc usecase.NotMeUsecase,
 ) proto_auth.AuthServiceServer {
 	userRepo := repo.NewUserRepository(db)
-	sessionRepo := repo.NewSessionRepository(db)
+	outboxRepo := repo.NewOutboxRepository(db)
+	publisher := event.NewOutboxPublisher(outboxRepo)
+	breachedPasswordRepo, err := repo.NewBreachedPasswordRepository(env.BreachedPasswordIndex)
+	// đã cấu hình index mà không nạp được thì dừng hẳn, không chạy tiếp khi tắt kiểm tra mật khẩu bị lộ
+	if err != nil {
+		log.Fatal("Failed to load breached password index: " + err.Error())
+	}
+	clientInfo, err := newClientInfoExtractor(env.TrustedProxies)
+	if err != nil {
+		log.Fatal("Failed to parse trusted proxies: " + err.Error())
+	}
 	tx := transaction.NewTransaction(db)
 	saga := saga.NewSagaManager()
-	argonService := hashpass.NewArgon()
+	argonService := hasher.NewHasher(hasher.ParamsFromEnv(env))
+	passwordHistoryUc := usecase.NewPasswordHistoryUsecase(
+		repo.NewPasswordHistoryRepository(db),
+		argonService,
+		env.PasswordHistorySize,
+	)
 	genUUID := goid.NewGoId().UUID()
-	tokenAccess := token.NewToken(env.JwtSecret.Access)
+	tokenAccess := accesstoken.NewIssuer(env.JwtSecret.Access)
 	tokenRefresh := token.NewToken(env.JwtSecret.Refresh)
 	tokenAuth := token.NewToken(env.JwtSecret.Verify)
 	tokenForgot := token.NewToken(env.JwtSecret.Forgot)
 	return &authService{
-		env:              env,
-		log:              log,
-		mailService:      mailService,
-		permissionClient: permissionClient,
-		uuid:             genUUID,
-		checkTokenUc:     usecase.NewCheckTokenUsecase(sessionRepo),
-		cache:            cache,
+		env:             env,
+		log:             log,
+		mailService:     mailService,
+		permissionCache: permissionCache,
+		uuid:            genUUID,
+		checkTokenUc:    usecase.NewCheckTokenUsecase(sessionRepo),
+		cache:           cache,
 		loginUc: usecase.NewLoginUsecase(
 			userRepo,
 			sessionRepo,
@@ -74,6 +109,8 @@ func NewAuthService(
 			tokenRefresh,
 			argonService,
 			cache,
+			refreshTokens,
+			time.Duration(env.PasswordExpiryDays)*24*time.Hour,
 		),
 		registerUc: usecase.NewRegisterUsecase(
 			userRepo,
@@ -83,14 +120,20 @@ func NewAuthService(
 			genUUID,
 			argonService,
 			cache,
-			queueClient,
+			outboxRepo,
 			saga,
+			passwordHistoryUc,
+			publisher,
+			workers,
 		),
 		refreshUc: usecase.NewRefreshUsecase(
+			userRepo,
 			sessionRepo,
 			tokenAccess,
 			tokenRefresh,
-			cache,
+			metrics.NewCache(cache, "refresh_session"),
+			refreshTokens,
+			workers,
 		),
 		logoutUc: usecase.NewLogoutUsecase(
 			sessionRepo,
@@ -101,7 +144,10 @@ func NewAuthService(
 			userRepo,
 			sessionRepo,
 			tokenAuth,
-			cache,
+			metrics.NewCache(cache, "verify_register"),
+			tx,
+			publisher,
+			workers,
 		),
 		forgotPasswordUc: usecase.NewForgotPasswordUsecase(
 			userRepo,
@@ -109,23 +155,34 @@ func NewAuthService(
 			tx,
 			tokenForgot,
 			cache,
-			queueClient,
+			outboxRepo,
 			saga,
 			log,
+			workers,
 		),
 		resetCodeUc: usecase.NewResetPasswordCodeUsecase(
 			userRepo,
 			sessionRepo,
+			tx,
 			cache,
 			tokenForgot,
 			argonService,
+			passwordHistoryUc,
+			publisher,
+			revocationUc,
+			workers,
 		),
 		resetTokenUc: usecase.NewResetPasswordTokenUsecase(
 			userRepo,
 			sessionRepo,
+			tx,
 			cache,
 			tokenForgot,
 			argonService,
+			passwordHistoryUc,
+			publisher,
+			revocationUc,
+			workers,
 		),
 		checkCodeUc: usecase.NewCheckCodeUsecase(
 			userRepo,
@@ -135,5 +192,16 @@ func NewAuthService(
 			userRepo,
 			cache,
 		),
+		passwordPolicyUc: usecase.NewPasswordPolicyUsecase(breachedPasswordRepo),
+		authEventUc:      usecase.NewAuthEventUsecase(repo.NewAuthEventRepository(db)),
+		deviceUc: usecase.NewDeviceRecognitionUsecase(
+			sessionRepo,
+			queueClient,
+		),
+		notMeUc:       notMeUc,
+		tokens:        newTokenExtractor(env.TokenMetadataKey),
+		clientInfo:    clientInfo,
+		healthChecker: healthChecker,
+		workers:       workers,
 	}
 }
@@ -0,0 +1,57 @@
+package grpcservice
+
+import (
+	"auth-service/domain/usecase"
+	"net/http"
+
+	"github.com/anhvanhoa/service-core/domain/user_context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Caller là người gọi đã xác thực của một route HTTP gateway
+type Caller struct {
+	UserID  string
+	Context *user_context.UserContext
+	// Session chỉ đúng khi người gọi dùng access token của phiên đăng nhập,
+	// sai với personal access token và token của service account
+	Session bool
+}
+
+// CallerAuthenticator xác thực người gọi các route HTTP gateway gọi thẳng usecase (chưa có RPC trong proto),
+// đọc token và kiểm tra CSRF giống hệt request đi qua interceptor gRPC.
+type CallerAuthenticator struct {
+	tokens   *tokenExtractor
+	contexts *UserContextResolver
+}
+
+func NewCallerAuthenticator(metadataKey string, contexts *UserContextResolver) *CallerAuthenticator {
+	return &CallerAuthenticator{
+		tokens:   newTokenExtractor(metadataKey),
+		contexts: contexts,
+	}
+}
+
+// Authenticate nhận metadata đã chuyển từ header HTTP; request không phải GET phải qua kiểm tra CSRF
+func (a *CallerAuthenticator) Authenticate(method string, md metadata.MD) (Caller, error) {
+	if method != http.MethodGet {
+		if err := CheckCsrf(md); err != nil {
+			return Caller{}, err
+		}
+	}
+	at := a.tokens.AccessToken(md)
+	if at == "" {
+		return Caller{}, status.Error(codes.Unauthenticated, "Cần đăng nhập để thực hiện thao tác này")
+	}
+	uCtx := a.contexts.Resolve(at)
+	if uCtx == nil || uCtx.UserID == "" {
+		return Caller{}, status.Error(codes.Unauthenticated, "Phiên đăng nhập không hợp lệ hoặc đã hết hạn")
+	}
+	_, _, serviceAccount := a.contexts.accessTokens.ServiceAccountOf(at)
+	return Caller{
+		UserID:  uCtx.UserID,
+		Context: uCtx,
+		Session: !serviceAccount && !usecase.IsPersonalAccessToken(at),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package grpcservice
+
+import (
+	"auth-service/constants"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckCsrf(t *testing.T) {
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want codes.Code
+	}{
+		{name: "bearer without cookies", md: metadata.Pairs("authorization", "Bearer abc"), want: codes.OK},
+		{name: "session cookie without header", md: metadata.Pairs(constants.HeaderCookie, "at=abc; csrf=x"), want: codes.PermissionDenied},
+		{name: "refresh cookie without header", md: metadata.Pairs(constants.HeaderCookie, "rt=abc; csrf=x"), want: codes.PermissionDenied},
+		{name: "header does not match", md: metadata.Pairs(constants.HeaderCookie, "at=abc; csrf=x", constants.HeaderCsrfToken, "y"), want: codes.PermissionDenied},
+		{name: "no csrf cookie", md: metadata.Pairs(constants.HeaderCookie, "at=abc", constants.HeaderCsrfToken, ""), want: codes.PermissionDenied},
+		{name: "header matches", md: metadata.Pairs(constants.HeaderCookie, "at=abc; csrf=x", constants.HeaderCsrfToken, "x"), want: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(CheckCsrf(tt.md)); got != tt.want {
+				t.Fatalf("CheckCsrf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsBeforeResolving(t *testing.T) {
+	// resolver nil: các trường hợp dưới đây phải bị từ chối trước khi tra user context
+	a := NewCallerAuthenticator("", nil)
+	tests := []struct {
+		name   string
+		method string
+		md     metadata.MD
+		want   codes.Code
+	}{
+		{name: "no token", method: http.MethodGet, md: metadata.MD{}, want: codes.Unauthenticated},
+		{name: "cookie post without csrf", method: http.MethodPost, md: metadata.Pairs(constants.HeaderCookie, "at=abc"), want: codes.PermissionDenied},
+		{name: "cookie delete without csrf", method: http.MethodDelete, md: metadata.Pairs(constants.HeaderCookie, "at=abc; csrf=x"), want: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.Authenticate(tt.method, tt.md)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("Authenticate = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
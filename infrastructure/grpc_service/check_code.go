@@ -1,6 +1,7 @@
 package grpcservice
 
 import (
+	"auth-service/domain/entity"
 	"context"
 
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
@@ -8,7 +9,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func (a *authService) CheckCode(ctx context.Context, req *proto_auth.CheckCodeRequest) (*proto_auth.CheckCodeResponse, error) {
+func (a *authService) CheckCode(ctx context.Context, req *proto_auth.CheckCodeRequest) (res *proto_auth.CheckCodeResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventCheckCode, "")
+	defer func() { a.recordAuthEvent(event, err) }()
+
 	valid, err := a.checkCodeUc.CheckCode(req.GetCode(), req.GetEmail())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
@@ -1,6 +1,7 @@
 package grpcservice
 
 import (
+	"auth-service/domain/entity"
 	"context"
 
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
@@ -8,7 +9,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func (a *authService) CheckToken(ctx context.Context, req *proto_auth.CheckTokenRequest) (*proto_auth.CheckTokenResponse, error) {
+func (a *authService) CheckToken(ctx context.Context, req *proto_auth.CheckTokenRequest) (res *proto_auth.CheckTokenResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventCheckToken, "")
+	defer func() { a.recordAuthEvent(event, err) }()
+
 	ok, err := a.checkTokenUc.CheckToken(req.GetToken())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
@@ -0,0 +1,141 @@
+package grpcservice
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	headerForwardedFor     = "x-forwarded-for"
+	headerRealIP           = "x-real-ip"
+	headerUserAgent        = "user-agent"
+	headerGatewayUserAgent = "grpcgateway-user-agent"
+)
+
+// loopback luôn được tin vì HTTP gateway gọi gRPC server trong cùng process
+var loopbackProxies = []netip.Prefix{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("::1/128"),
+}
+
+// clientInfoExtractor lấy IP và user agent của người gọi. X-Forwarded-For, X-Real-IP và grpcgateway-user-agent
+// chỉ được đọc khi kết nối tới từ proxy tin cậy, vì client gọi gRPC trực tiếp có thể tự đặt các header này.
+type clientInfoExtractor struct {
+	trusted []netip.Prefix
+}
+
+// newClientInfoExtractor nhận danh sách IP hoặc CIDR của proxy tin cậy
+func newClientInfoExtractor(trustedProxies []string) (*clientInfoExtractor, error) {
+	trusted := append([]netip.Prefix{}, loopbackProxies...)
+	for _, v := range trustedProxies {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(v); err == nil {
+			trusted = append(trusted, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", v)
+		}
+		trusted = append(trusted, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return &clientInfoExtractor{trusted: trusted}, nil
+}
+
+func (e *clientInfoExtractor) ClientInfo(ctx context.Context) (string, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	peerIP := peerAddr(ctx)
+	if !e.isTrusted(peerIP) {
+		return addrString(peerIP), firstValue(md, headerUserAgent)
+	}
+	ip := e.forwardedFor(md)
+	if !ip.IsValid() {
+		ip, _ = parseAddr(firstValue(md, headerRealIP))
+	}
+	if !ip.IsValid() {
+		ip = peerIP
+	}
+	// Request qua HTTP gateway mang user-agent của trình duyệt trong grpcgateway-user-agent
+	userAgent := firstValue(md, headerGatewayUserAgent)
+	if userAgent == "" {
+		userAgent = firstValue(md, headerUserAgent)
+	}
+	return addrString(ip), userAgent
+}
+
+// forwardedFor duyệt X-Forwarded-For từ phải sang trái, bỏ qua các proxy tin cậy và trả về hop đầu tiên không tin cậy;
+// các giá trị bên trái hop đó do client tự đặt nên không được dùng
+func (e *clientInfoExtractor) forwardedFor(md metadata.MD) netip.Addr {
+	var hops []string
+	for _, v := range md.Get(headerForwardedFor) {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	var last netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseAddr(hops[i])
+		if !ok {
+			break
+		}
+		last = addr
+		if !e.isTrusted(addr) {
+			return addr
+		}
+	}
+	return last
+}
+
+func (e *clientInfoExtractor) isTrusted(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	for _, prefix := range e.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerAddr(ctx context.Context) netip.Addr {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}
+	}
+	host := p.Addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	addr, _ := parseAddr(host)
+	return addr
+}
+
+func parseAddr(v string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(v))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func addrString(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+	return addr.String()
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
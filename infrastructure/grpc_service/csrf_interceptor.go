@@ -0,0 +1,57 @@
+package grpcservice
+
+import (
+	"auth-service/constants"
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var authServicePrefix = "/" + proto_auth.AuthService_ServiceDesc.ServiceName + "/"
+
+// Các RPC chỉ đọc, không đổi trạng thái nên không cần CSRF token; mọi RPC khác của AuthService đều được kiểm tra
+var csrfSafeMethods = map[string]bool{
+	proto_auth.AuthService_Profile_FullMethodName:    true,
+	proto_auth.AuthService_CheckToken_FullMethodName: true,
+	proto_auth.AuthService_CheckCode_FullMethodName:  true,
+}
+
+// CsrfInterceptor kiểm tra double-submit token: khi request mang cookie phiên,
+// header x-csrf-token phải trùng với cookie csrf được cấp lúc đăng nhập.
+// Request không có cookie phiên (client gRPC truyền token trong body) không bị kiểm tra
+// vì trình duyệt không tự gửi kèm token đó.
+func CsrfInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, authServicePrefix) || csrfSafeMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		if err := CheckCsrf(md); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// CheckCsrf trả lỗi PermissionDenied nếu request mang cookie phiên mà header x-csrf-token không trùng cookie csrf
+func CheckCsrf(md metadata.MD) error {
+	if cookieValue(md, constants.KeyCookieAccessToken) == "" &&
+		cookieValue(md, constants.KeyCookieRefreshToken) == "" {
+		return nil
+	}
+	cookie := cookieValue(md, constants.KeyCookieCsrfToken)
+	var header string
+	if vals := md.Get(constants.HeaderCsrfToken); len(vals) > 0 {
+		header = vals[0]
+	}
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+		return status.Error(codes.PermissionDenied, "CSRF token không hợp lệ")
+	}
+	return nil
+}
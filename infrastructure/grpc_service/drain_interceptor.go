@@ -0,0 +1,61 @@
+package grpcservice
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Drainer đếm các RPC đang xử lý để khi tắt service có thể chờ chúng hoàn tất trước khi dừng server
+type Drainer struct {
+	mu       sync.RWMutex
+	draining bool
+	inflight sync.WaitGroup
+}
+
+func NewDrainer() *Drainer {
+	return &Drainer{}
+}
+
+// Interceptor từ chối RPC mới bằng Unavailable sau khi bắt đầu drain để client thử lại ở instance khác.
+// Health check luôn được trả lời để load balancer thấy trạng thái NOT_SERVING.
+func (d *Drainer) Interceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+			return handler(ctx, req)
+		}
+		d.mu.RLock()
+		if d.draining {
+			d.mu.RUnlock()
+			return nil, status.Error(codes.Unavailable, "Dịch vụ đang tắt, vui lòng thử lại sau")
+		}
+		d.inflight.Add(1)
+		d.mu.RUnlock()
+		defer d.inflight.Done()
+		return handler(ctx, req)
+	}
+}
+
+// Drain chờ các RPC đang xử lý tới khi xong hoặc hết ctx
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
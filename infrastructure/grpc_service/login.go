@@ -1,6 +1,10 @@
 package grpcservice
 
 import (
+	"auth-service/constants"
+	"auth-service/domain/entity"
+	"auth-service/domain/usecase"
+	"auth-service/infrastructure/grpc_client"
 	"context"
 	"regexp"
 	"time"
@@ -8,19 +12,24 @@ import (
 	"github.com/anhvanhoa/service-core/domain/user_context"
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
 	proto_user_role "github.com/anhvanhoa/sf-proto/gen/user_role/v1"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func (a *authService) Login(ctx context.Context, req *proto_auth.LoginRequest) (*proto_auth.LoginResponse, error) {
-	client := a.permissionClient.UserRoleService()
+func (a *authService) Login(ctx context.Context, req *proto_auth.LoginRequest) (res *proto_auth.LoginResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventLogin, req.GetOs())
+	defer func() { a.recordAuthEvent(event, err) }()
+
 	identifier := req.GetEmailOrPhone()
 	if !isValidEmail(identifier) && !isValidPhone(identifier) {
 		return nil, status.Errorf(codes.InvalidArgument, "Email hoặc số điện thoại không đúng định dạng")
 	}
 
 	user, err := a.loginUc.GetUserByEmailOrPhone(req.GetEmailOrPhone())
+	event.UserID = user.ID
 	if err != nil {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
@@ -29,31 +38,52 @@ func (a *authService) Login(ctx context.Context, req *proto_auth.LoginRequest) (
 		return nil, status.Errorf(codes.InvalidArgument, "Mật khẩu không chính xác")
 	}
 
+	if user.IsSuspended() {
+		return nil, status.Errorf(codes.PermissionDenied, "Tài khoản đã bị khóa")
+	}
+
+	if err := a.loginUc.UpgradePasswordHash(user, req.GetPassword()); err != nil {
+		a.log.Error("Failed to upgrade password hash: " + err.Error())
+	}
+
+	if a.loginUc.IsPasswordExpired(user) {
+		event.Reason = "password_expired"
+		return a.loginPasswordExpired(ctx, user, req.GetOs())
+	}
+
+	device := a.getDevice(ctx, req.GetOs())
+	knownDevice, checkErr := a.deviceUc.IsKnownDevice(user.ID, device)
+	if checkErr != nil {
+		a.log.Error("Failed to check login device: " + checkErr.Error())
+	}
+
+	var organizationID string
+	if user.ActiveOrganizationID != nil {
+		organizationID = *user.ActiveOrganizationID
+	}
 	exp := time.Now().Add(15 * time.Minute)
-	accessToken, err := a.loginUc.GengerateAccessToken(user.ID, user.FullName, user.Email, exp)
+	accessToken, err := a.loginUc.GengerateAccessToken(user.ID, user.FullName, user.Email, organizationID, exp)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Không thể tạo access token")
 	}
 
 	refreshExp := time.Now().Add(7 * 24 * time.Hour)
-	refreshToken, err := a.loginUc.GengerateRefreshToken(user.ID, user.FullName, user.Email, refreshExp, req.GetOs())
+	refreshToken, err := a.loginUc.GengerateRefreshToken(user.ID, user.FullName, user.Email, refreshExp, device)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Không thể tạo refresh token")
 	}
 
-	permissions, err := client.GetUserPermissions(ctx, &proto_user_role.GetUserPermissionsRequest{
-		UserId: user.ID,
-	})
+	permissions, err := a.permissionCache.Resolve(ctx, grpc_client.PermissionRPCLogin, user.ID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Không thể lấy quyền")
+		a.log.Error("Failed to resolve permissions: " + err.Error())
+		return nil, status.Errorf(codes.Unavailable, "Không thể lấy quyền, vui lòng thử lại sau")
 	}
-
-	uCtx := a.convertPermissions(permissions)
-	bytes, err := uCtx.ToBytes()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Không thể chuyển đổi quyền")
+	if permissions.Stale {
+		event.Reason = "permissions_stale"
 	}
-	if err := a.cache.Set(accessToken, bytes, time.Until(exp)); err != nil {
+
+	uCtx := convertPermissions(permissions.Permissions)
+	if err := a.permissionCache.Store(user.ID, accessToken, uCtx, exp); err != nil {
 		return nil, status.Errorf(codes.Internal, "Không thể lưu quyền")
 	}
 
@@ -71,6 +101,15 @@ func (a *authService) Login(ctx context.Context, req *proto_auth.LoginRequest) (
 		userInfo.Birthday = timestamppb.New(*user.Birthday)
 	}
 
+	if !knownDevice {
+		a.workers.Go("notify_new_device", func(ctx context.Context) error {
+			a.notifyNewDevice(ctx, user, device, refreshToken, refreshExp)
+			return nil
+		})
+	}
+
+	a.setSessionCookies(ctx, accessToken, refreshToken)
+
 	return &proto_auth.LoginResponse{
 		User:         userInfo,
 		AccessToken:  accessToken,
@@ -79,16 +118,41 @@ func (a *authService) Login(ctx context.Context, req *proto_auth.LoginRequest) (
 	}, nil
 }
 
-func (a *authService) convertPermissions(data *proto_user_role.GetUserPermissionsResponse) *user_context.UserContext {
+// loginPasswordExpired không cấp access/refresh token mà chỉ cấp token đổi mật khẩu,
+// token này chỉ dùng được với ResetPasswordByToken. Token nằm trong header riêng, không trong AccessToken,
+// để client không nhầm nó là access token.
+func (a *authService) loginPasswordExpired(ctx context.Context, user entity.User, os string) (*proto_auth.LoginResponse, error) {
+	result, err := a.forgotPasswordUc.ForgotPassword(user.Email, os, usecase.ForgotByToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Không thể tạo phiên đổi mật khẩu")
+	}
+	md := metadata.Pairs(
+		constants.HeaderPasswordExpired, "true",
+		constants.HeaderPasswordChangeToken, result.Token,
+	)
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		return nil, status.Errorf(codes.Internal, "Không thể tạo phiên đổi mật khẩu")
+	}
+	return &proto_auth.LoginResponse{
+		User:    a.createUserInfo(user.GetInfor()),
+		Message: "Mật khẩu đã hết hạn, vui lòng đổi mật khẩu để tiếp tục",
+	}, nil
+}
+
+func convertPermissions(data *proto_user_role.GetUserPermissionsResponse) *user_context.UserContext {
 	uCtx := user_context.NewUserContext()
 	uCtx.UserID = data.UserId
-	scopes := make([]user_context.Scope, len(data.Scopes))
-	for i, scope := range data.Scopes {
-		scopes[i] = user_context.Scope{
+	scopes := make([]user_context.Scope, 0, len(data.Scopes))
+	for _, scope := range data.Scopes {
+		// scope principal do auth-service tự gắn, không nhận từ permission service để người dùng không giả làm service account
+		if scope.Resource == entity.PrincipalScopeResource {
+			continue
+		}
+		scopes = append(scopes, user_context.Scope{
 			Resource:     scope.Resource,
 			ResourceData: scope.ResourceData,
 			Action:       scope.Action,
-		}
+		})
 	}
 	uCtx.Scopes = scopes
 	permissions := make([]user_context.Permission, len(data.Permissions))
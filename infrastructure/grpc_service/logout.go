@@ -1,26 +1,42 @@
 package grpcservice
 
 import (
+	"auth-service/domain/entity"
 	"context"
 
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-func (a *authService) Logout(ctx context.Context, req *proto_auth.LogoutRequest) (*proto_auth.LogoutResponse, error) {
-	if _, err := a.logoutUc.VerifyToken(req.GetRefreshToken()); err != nil {
+func (a *authService) Logout(ctx context.Context, req *proto_auth.LogoutRequest) (res *proto_auth.LogoutResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventLogout, "")
+	defer func() { a.recordAuthEvent(event, err) }()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	at, rt := req.GetAccessToken(), req.GetRefreshToken()
+	if rt == "" {
+		rt = a.tokens.RefreshToken(md)
+	}
+	if at == "" {
+		at = a.tokens.AccessToken(md)
+	}
+	userID, err := a.logoutUc.VerifyToken(rt)
+	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "Đăng xuất thất bại")
 	}
+	event.UserID = userID
 
-	if err := a.logoutUc.Logout(req.GetRefreshToken()); err != nil {
+	if err := a.logoutUc.Logout(rt); err != nil {
 		return nil, status.Error(codes.Internal, "Đăng xuất thất bại")
 	}
 
-	if err := a.cache.Delete(req.GetAccessToken()); err != nil {
+	if err := a.cache.Delete(at); err != nil {
 		return nil, status.Errorf(codes.Internal, "Đăng xuất thất bại")
 	}
 
+	a.clearSessionCookies(ctx)
 	return &proto_auth.LogoutResponse{
 		Message: "Đăng xuất thành công",
 	}, nil
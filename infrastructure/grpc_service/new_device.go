@@ -0,0 +1,88 @@
+package grpcservice
+
+import (
+	"auth-service/constants"
+	"auth-service/domain/entity"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/queue"
+	proto_mail_history "github.com/anhvanhoa/sf-proto/gen/mail_history/v1"
+	proto_mail_template "github.com/anhvanhoa/sf-proto/gen/mail_tmpl/v1"
+	proto_status_history "github.com/anhvanhoa/sf-proto/gen/status_history/v1"
+)
+
+func (a *authService) getDevice(ctx context.Context, os string) entity.Device {
+	ip, userAgent := a.getClientInfo(ctx)
+	return entity.Device{
+		Os:        os,
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+}
+
+// notifyNewDevice gửi mail cảnh báo đăng nhập từ thiết bị lạ. Link "không phải tôi" mang token gắn với
+// refresh token của lần đăng nhập này, xác nhận qua POST /v1/auth/not-me sẽ thu hồi phiên và buộc đổi mật khẩu.
+func (a *authService) notifyNewDevice(ctx context.Context, user entity.User, device entity.Device, refreshToken string, refreshExp time.Time) {
+	if a.mailService == nil || a.mailService.Mtc == nil || a.mailService.Mhc == nil || a.mailService.Shc == nil {
+		return
+	}
+	notMeToken, err := a.notMeUc.Issue(ctx, user.ID, refreshToken, refreshExp)
+	if err != nil {
+		a.log.Error("Failed to create not-me token: " + err.Error())
+		return
+	}
+
+	tmpl, err := a.mailService.Mtc.GetMailTmpl(ctx, &proto_mail_template.GetMailTmplRequest{
+		Id: constants.TPL_DEVICE_MAIL,
+	})
+	if err != nil {
+		a.log.Error("Failed to get new device mail template: " + err.Error())
+		return
+	}
+
+	data := map[string]any{
+		"user": user.GetInfor(),
+		"device": map[string]string{
+			"os":         device.Os,
+			"ip":         device.IP,
+			"user_agent": device.UserAgent,
+		},
+		"time": time.Now().Format(time.RFC3339),
+		"link": fmt.Sprintf("%s/auth/not-me/%s", a.env.FrontendUrl, notMeToken),
+	}
+	payload := queue.NewPayloadMail(data, []string{user.Email}, tmpl.MailTmpl.Id)
+	taskId, err := a.deviceUc.SendMail(payload)
+	if err != nil {
+		a.log.Error("Failed to enqueue new device mail: " + err.Error())
+		return
+	}
+
+	protoData, err := json.Marshal(&data)
+	if err != nil {
+		a.log.Error("Failed to marshal new device mail data: " + err.Error())
+		return
+	}
+	if _, err := a.mailService.Mhc.CreateMailHistory(ctx, &proto_mail_history.CreateMailHistoryRequest{
+		Id:            taskId,
+		TemplateId:    tmpl.MailTmpl.Id,
+		Subject:       tmpl.MailTmpl.Subject,
+		Body:          tmpl.MailTmpl.Body,
+		Tos:           []string{user.Email},
+		Data:          string(protoData),
+		EmailProvider: tmpl.MailTmpl.ProviderEmail,
+	}); err != nil {
+		a.log.Error("Failed to create new device mail history: " + err.Error())
+		return
+	}
+	if _, err := a.mailService.Shc.CreateStatusHistory(ctx, &proto_status_history.CreateStatusHistoryRequest{
+		MailHistoryId: taskId,
+		Status:        "pending",
+		Message:       "Send new device alert to " + user.Email,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+	}); err != nil {
+		a.log.Error("Failed to create new device status history: " + err.Error())
+	}
+}
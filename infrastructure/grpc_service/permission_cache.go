@@ -0,0 +1,83 @@
+package grpcservice
+
+import (
+	"auth-service/domain/repository"
+	"auth-service/infrastructure/grpc_client"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	"github.com/anhvanhoa/service-core/domain/user_context"
+)
+
+// PermissionCache lưu quyền theo access token (để interceptor tra cứu) kèm chỉ mục user -> token,
+// nhờ đó khi vai trò thay đổi có thể ghi lại quyền mới cho mọi access token còn sống của user.
+type PermissionCache struct {
+	cache    cache.CacheI
+	tokens   repository.TokenIndex
+	resolver grpc_client.PermissionResolver
+}
+
+func NewPermissionCache(cache cache.CacheI, tokens repository.TokenIndex, resolver grpc_client.PermissionResolver) *PermissionCache {
+	return &PermissionCache{
+		cache:    cache,
+		tokens:   tokens,
+		resolver: resolver,
+	}
+}
+
+func (p *PermissionCache) Resolve(ctx context.Context, rpc string, userID string) (grpc_client.PermissionResult, error) {
+	return p.resolver.Resolve(ctx, rpc, userID)
+}
+
+// Store ghi quyền cho access token và thêm token vào chỉ mục của user
+func (p *PermissionCache) Store(userID, accessToken string, uCtx *user_context.UserContext, exp time.Time) error {
+	bytes, err := uCtx.ToBytes()
+	if err != nil {
+		return err
+	}
+	if err := p.cache.Set(accessToken, bytes, time.Until(exp)); err != nil {
+		return err
+	}
+	return p.tokens.Add(context.Background(), userID, accessToken, exp)
+}
+
+// InvalidateUser lấy lại quyền của user và ghi đè lên mọi access token còn sống.
+// Nếu không lấy được quyền mới thì xóa các token khỏi cache, client sẽ phải refresh để nhận quyền hiện tại.
+// Chỉ mục được lấy ra và xóa trong một bước, token đăng nhập trong lúc xử lý được ghi vào chỉ mục mới
+// nên không bị xóa mất; các token còn sống được thêm lại sau khi ghi đè.
+func (p *PermissionCache) InvalidateUser(ctx context.Context, userID string) (int, error) {
+	tokens, err := p.tokens.Take(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var bytes []byte
+	if res, err := p.resolver.Resolve(ctx, grpc_client.PermissionRPCRefreshToken, userID); err == nil && !res.Stale {
+		bytes, _ = convertPermissions(res.Permissions).ToBytes()
+	}
+	var errs []error
+	if bytes == nil {
+		errs = append(errs, p.resolver.Forget(userID))
+		for token := range tokens {
+			errs = append(errs, p.cache.Delete(token))
+		}
+		return len(tokens), errors.Join(errs...)
+	}
+
+	updated := 0
+	for token, exp := range tokens {
+		// Token đã logout thì không ghi lại và không đưa lại vào chỉ mục
+		if v, getErr := p.cache.Get(token); getErr != nil || v == nil {
+			continue
+		}
+		if err := p.cache.Set(token, bytes, time.Until(exp)); err != nil {
+			errs = append(errs, err)
+		} else {
+			updated++
+		}
+		errs = append(errs, p.tokens.Add(ctx, userID, token, exp))
+	}
+	return updated, errors.Join(errs...)
+}
@@ -1,57 +1,82 @@
 package grpcservice
 
 import (
+	"auth-service/domain/entity"
+	"auth-service/domain/usecase"
+	"auth-service/infrastructure/grpc_client"
 	"context"
-	"fmt"
+	"errors"
 	"time"
 
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
-	proto_user_role "github.com/anhvanhoa/sf-proto/gen/user_role/v1"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-func (a *authService) RefreshToken(ctx context.Context, req *proto_auth.RefreshTokenRequest) (*proto_auth.RefreshTokenResponse, error) {
-	if !a.refreshUc.CheckSessionByToken(req.GetRefreshToken()) {
+func (a *authService) RefreshToken(ctx context.Context, req *proto_auth.RefreshTokenRequest) (res *proto_auth.RefreshTokenResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventRefreshToken, req.GetOs())
+	defer func() { a.recordAuthEvent(event, err) }()
+
+	rt := req.GetRefreshToken()
+	if rt == "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		rt = a.tokens.RefreshToken(md)
+	}
+	if !a.refreshUc.CheckSessionByToken(rt) {
 		return nil, status.Error(codes.InvalidArgument, "Phiên làm việc không hợp lệ")
 	}
 
-	claims, err := a.refreshUc.VerifyToken(req.GetRefreshToken())
+	claims, err := a.refreshUc.VerifyToken(rt)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "Token không hợp lệ")
 	}
+	event.UserID = claims.Data.Id
 
-	if err := a.refreshUc.ClearSessionExpired(); err != nil {
-		a.log.Info(fmt.Sprintf("Clear expired sessions: %v", err))
+	// user đọc lại mỗi lần refresh để token mới phản ánh lần đổi tổ chức gần nhất
+	// và không cấp token cho tài khoản đã bị khóa hoặc bị buộc đổi mật khẩu
+	user, err := a.refreshUc.GetUser(claims.Data.Id)
+	if errors.Is(err, usecase.ErrUserSuspended) {
+		event.Reason = "suspended"
+		return nil, status.Error(codes.PermissionDenied, "Tài khoản đã bị khóa")
+	}
+	if errors.Is(err, usecase.ErrPasswordResetRequired) {
+		event.Reason = "password_reset_required"
+		return nil, status.Error(codes.PermissionDenied, "Mật khẩu cần được đổi, vui lòng đăng nhập lại")
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Người dùng không tồn tại")
+	}
+	var organizationID string
+	if user.ActiveOrganizationID != nil {
+		organizationID = *user.ActiveOrganizationID
 	}
-
 	accessExp := time.Now().Add(15 * time.Minute)
-	accessToken, err := a.refreshUc.GengerateAccessToken(claims.Data.Id, claims.Data.FullName, claims.Data.Email, accessExp)
+	accessToken, err := a.refreshUc.GengerateAccessToken(claims.Data.Id, claims.Data.FullName, claims.Data.Email, organizationID, accessExp)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Không thể tạo access token")
 	}
 
 	refreshExp := time.Now().Add(7 * 24 * time.Hour)
-	refreshToken, err := a.refreshUc.GengerateRefreshToken(claims.Data.Id, claims.Data.FullName, claims.Data.Email, refreshExp, req.GetOs())
+	refreshToken, err := a.refreshUc.GengerateRefreshToken(claims.Data.Id, claims.Data.FullName, claims.Data.Email, refreshExp, a.getDevice(ctx, req.GetOs()))
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Không thể tạo refresh token")
 	}
 
-	permissions, err := a.permissionClient.UserRoleService().GetUserPermissions(ctx, &proto_user_role.GetUserPermissionsRequest{
-		UserId: claims.Data.Id,
-	})
+	permissions, err := a.permissionCache.Resolve(ctx, grpc_client.PermissionRPCRefreshToken, claims.Data.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Không thể lấy quyền")
+		a.log.Error("Failed to resolve permissions: " + err.Error())
+		return nil, status.Errorf(codes.Unavailable, "Không thể lấy quyền, vui lòng thử lại sau")
 	}
-	uCtx := a.convertPermissions(permissions)
-	bytes, err := uCtx.ToBytes()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Không thể chuyển đổi quyền")
+	if permissions.Stale {
+		event.Reason = "permissions_stale"
 	}
-	if err := a.cache.Set(accessToken, bytes, time.Until(accessExp)); err != nil {
+	uCtx := convertPermissions(permissions.Permissions)
+	if err := a.permissionCache.Store(claims.Data.Id, accessToken, uCtx, accessExp); err != nil {
 		return nil, status.Errorf(codes.Internal, "Không thể lưu quyền")
 	}
 
+	a.setSessionCookies(ctx, accessToken, refreshToken)
 	return &proto_auth.RefreshTokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
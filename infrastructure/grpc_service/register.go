@@ -1,31 +1,36 @@
 package grpcservice
 
 import (
-	"auth-service/constants"
+	"auth-service/domain/entity"
 	"auth-service/domain/usecase"
+	"auth-service/infrastructure/health"
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
-	"github.com/anhvanhoa/service-core/domain/queue"
 	"github.com/anhvanhoa/service-core/domain/saga"
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
-	proto_mail_history "github.com/anhvanhoa/sf-proto/gen/mail_history/v1"
-	proto_mail_template "github.com/anhvanhoa/sf-proto/gen/mail_tmpl/v1"
-	proto_status_history "github.com/anhvanhoa/sf-proto/gen/status_history/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func (a *authService) Register(ctx context.Context, req *proto_auth.RegisterRequest) (*proto_auth.RegisterResponse, error) {
+func (a *authService) Register(ctx context.Context, req *proto_auth.RegisterRequest) (res *proto_auth.RegisterResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventRegister, "")
+	defer func() { a.recordAuthEvent(event, err) }()
+
+	if !a.healthChecker.Serving(health.ServiceRegister) {
+		return nil, status.Error(codes.Unavailable, "Chức năng đăng ký tạm thời không khả dụng")
+	}
 	if err := a.validateWhenRegister(); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	if err := validatePasswordMatch(req.GetPassword(), req.GetConfirmPassword()); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	if err := a.passwordPolicyUc.Validate(req.GetPassword()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	existingUser, err := a.registerUc.CheckUserExist(req.GetEmail())
 	if err == nil && existingUser {
 		return nil, status.Error(codes.AlreadyExists, "Email đã được sử dụng")
@@ -34,8 +39,9 @@ func (a *authService) Register(ctx context.Context, req *proto_auth.RegisterRequ
 	var result usecase.ResRegister
 	exp := time.Now().Add(15 * time.Minute)
 	os := "web"
+	event.Os = os
 	sagaId := fmt.Sprintf("register-%s-%s", req.GetEmail(), a.uuid.Gen())
-	err = a.registerUc.RegisterWithSaga(sagaId, func(ctx context.Context, sagaTx saga.SagaTransactionI) error {
+	err = a.registerUc.RegisterWithSaga(ctx, sagaId, func(ctx context.Context, sagaTx saga.SagaTransactionI) error {
 		code := a.registerUc.GengerateCode(6)
 		registerReq := usecase.RegisterReq{
 			Email:           req.GetEmail(),
@@ -43,18 +49,14 @@ func (a *authService) Register(ctx context.Context, req *proto_auth.RegisterRequ
 			Password:        req.GetPassword(),
 			ConfirmPassword: req.GetConfirmPassword(),
 			Code:            code,
+			VerifyLink:      a.env.FrontendUrl + "/auth/verify/",
 		}
-		var err error
-		var data map[string]any
 		sagaTx.AddStep(
-			saga.NewSagaStep(
+			newSagaStep(
 				"Register",
 				func(ctx context.Context) error {
+					var err error
 					result, err = a.registerUc.Register(registerReq, os, exp)
-					data = map[string]any{
-						"user": result.UserInfor,
-						"link": a.env.FrontendUrl + "/auth/verify/" + result.Token,
-					}
 					return err
 				},
 				func(ctx context.Context) error {
@@ -62,86 +64,9 @@ func (a *authService) Register(ctx context.Context, req *proto_auth.RegisterRequ
 				},
 			),
 		)
-		var tmpl *proto_mail_template.GetMailTmplResponse
-		sagaTx.AddStep(saga.NewSagaStep(
-			"GetMailTemplate",
-			func(ctx context.Context) error {
-				if tmpl, err = a.mailService.Mtc.GetMailTmpl(ctx, &proto_mail_template.GetMailTmplRequest{
-					Id: constants.TPL_REGISTER_MAIL,
-				}); err != nil {
-					return err
-				}
-				return nil
-			}, nil,
-		))
-
-		var taskId string
-		sagaTx.AddStep(saga.NewSagaStep(
-			"SendMail",
-			func(ctx context.Context) error {
-				payload := queue.NewPayloadMail(data, []string{result.UserInfor.Email}, tmpl.MailTmpl.Id)
-				if taskId, err = a.registerUc.SendMail(payload); err != nil {
-					return err
-				}
-				return nil
-			},
-			func(ctx context.Context) error {
-				return a.registerUc.CompensateSendMail(ctx, taskId)
-			},
-		))
-
-		sagaTx.AddStep(saga.NewSagaStep(
-			"CreateMailHistory",
-			func(ctx context.Context) error {
-				protoData, err := json.Marshal(&data)
-				if err != nil {
-					return err
-				}
-				if _, err := a.mailService.Mhc.CreateMailHistory(ctx, &proto_mail_history.CreateMailHistoryRequest{
-					Id:            taskId,
-					TemplateId:    constants.TPL_REGISTER_MAIL,
-					Subject:       tmpl.MailTmpl.Subject,
-					Body:          tmpl.MailTmpl.Body,
-					Tos:           []string{result.UserInfor.Email},
-					Data:          string(protoData),
-					EmailProvider: tmpl.MailTmpl.ProviderEmail,
-				}); err != nil {
-					return err
-				}
-				return nil
-			},
-			func(ctx context.Context) error {
-				a.mailService.Mhc.DeleteMailHistory(ctx, &proto_mail_history.DeleteMailHistoryRequest{
-					Id: taskId,
-				})
-				return nil
-			},
-		))
-
-		sagaTx.AddStep(saga.NewSagaStep(
-			"CreateStatusHistory",
-			func(ctx context.Context) error {
-				if _, err := a.mailService.Shc.CreateStatusHistory(ctx, &proto_status_history.CreateStatusHistoryRequest{
-					MailHistoryId: taskId,
-					Status:        "pending",
-					Message:       "Send mail to " + result.UserInfor.Email,
-					CreatedAt:     time.Now().Format(time.RFC3339),
-				}); err != nil {
-					return err
-				}
-				return nil
-			},
-			func(ctx context.Context) error {
-				a.mailService.Shc.DeleteStatusHistory(ctx, &proto_status_history.DeleteStatusHistoryRequest{
-					Status:        "pending",
-					MailHistoryId: taskId,
-				})
-				return nil
-			},
-		))
-
 		return nil
 	})
+	event.UserID = result.UserInfor.ID
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Đăng ký thất bại: "+err.Error())
 	}
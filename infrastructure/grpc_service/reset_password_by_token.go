@@ -1,27 +1,41 @@
 package grpcservice
 
 import (
+	"auth-service/domain/entity"
+	"auth-service/domain/usecase"
 	"context"
+	"errors"
 
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func (a *authService) ResetPasswordByToken(ctx context.Context, req *proto_auth.ResetPasswordByTokenRequest) (*proto_auth.ResetPasswordByTokenResponse, error) {
+func (a *authService) ResetPasswordByToken(ctx context.Context, req *proto_auth.ResetPasswordByTokenRequest) (res *proto_auth.ResetPasswordByTokenResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventResetPass, "")
+	defer func() { a.recordAuthEvent(event, err) }()
+
 	// Business logic validation: check if passwords match
 	if req.GetNewPassword() != req.GetConfirmPassword() {
 		return nil, status.Error(codes.InvalidArgument, "Mật khẩu mới và xác nhận mật khẩu không khớp")
 	}
 
+	if err := a.passwordPolicyUc.Validate(req.GetNewPassword()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// Verify session
 	userID, err := a.resetTokenUc.VerifySession(req.GetToken())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	event.UserID = userID
 
 	// Reset password
 	if err := a.resetTokenUc.ResetPass(userID, req.GetNewPassword(), req.GetConfirmPassword()); err != nil {
+		if errors.Is(err, usecase.ErrPasswordReused) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
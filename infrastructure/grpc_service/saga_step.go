@@ -0,0 +1,32 @@
+package grpcservice
+
+import (
+	"auth-service/infrastructure/metrics"
+	"auth-service/infrastructure/tracing"
+	"context"
+
+	"github.com/anhvanhoa/service-core/domain/saga"
+)
+
+// newSagaStep giống saga.NewSagaStep nhưng tạo span và đếm số bước lỗi, số lần compensate
+func newSagaStep(name string, execute, compensate func(ctx context.Context) error) *saga.SagaStep {
+	return saga.NewSagaStep(
+		name,
+		func(ctx context.Context) error {
+			ctx, span := tracing.Start(ctx, "saga."+name)
+			err := execute(ctx)
+			if err != nil {
+				metrics.SagaStepFailed(name)
+			}
+			tracing.End(span, err)
+			return err
+		},
+		func(ctx context.Context) error {
+			ctx, span := tracing.Start(ctx, "saga."+name+".compensate")
+			err := compensate(ctx)
+			metrics.SagaCompensated(name, err)
+			tracing.End(span, err)
+			return err
+		},
+	)
+}
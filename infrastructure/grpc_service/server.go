@@ -2,12 +2,14 @@ package grpcservice
 
 import (
 	"auth-service/bootstrap"
+	"auth-service/infrastructure/health"
+	"auth-service/infrastructure/metrics"
+	"auth-service/infrastructure/tracing"
 
 	grpc_service "github.com/anhvanhoa/service-core/bootstrap/grpc"
 	"github.com/anhvanhoa/service-core/domain/cache"
 	"github.com/anhvanhoa/service-core/domain/log"
 	"github.com/anhvanhoa/service-core/domain/token"
-	"github.com/anhvanhoa/service-core/domain/user_context"
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
 	"google.golang.org/grpc"
 )
@@ -17,6 +19,9 @@ func NewGRPCServer(
 	cacher cache.CacheI,
 	log *log.LogGRPCImpl,
 	authService proto_auth.AuthServiceServer,
+	healthChecker *health.Checker,
+	drainer *Drainer,
+	userContexts *UserContextResolver,
 ) *grpc_service.GRPCServer {
 	config := &grpc_service.GRPCServerConfig{
 		IsProduction: env.IsProduction(),
@@ -32,7 +37,12 @@ func NewGRPCServer(
 		log,
 		func(server *grpc.Server) {
 			proto_auth.RegisterAuthServiceServer(server, authService)
+			healthChecker.Register(server)
 		},
+		drainer.Interceptor(),
+		tracing.UnaryServerInterceptor(),
+		metrics.UnaryServerInterceptor(),
+		CsrfInterceptor(),
 		middleware.AuthorizationInterceptor(
 			env.SecretService,
 			func(action string, resource string) bool {
@@ -42,15 +52,7 @@ func NewGRPCServer(
 				}
 				return hasPermission != nil && string(hasPermission) == "true"
 			},
-			func(at string) *user_context.UserContext {
-				userData, err := cacher.Get(at)
-				if err != nil || userData == nil {
-					return nil
-				}
-				uCtx := user_context.NewUserContext()
-				uCtx.FromBytes(userData)
-				return uCtx
-			},
+			userContexts.Resolve,
 		),
 	)
 }
@@ -0,0 +1,94 @@
+package grpcservice
+
+import (
+	"auth-service/constants"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	accessCookieMaxAge  = 15 * time.Minute
+	refreshCookieMaxAge = 7 * 24 * time.Hour
+)
+
+func (a *authService) cookieMode() bool {
+	return a.env.SessionCookie != nil && a.env.SessionCookie.Enabled
+}
+
+func (a *authService) newCookie(name, value string, maxAge time.Duration, httpOnly bool) string {
+	cfg := a.env.SessionCookie
+	c := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: httpOnly,
+		Secure:   a.env.IsProduction(),
+		SameSite: http.SameSiteLaxMode,
+	}
+	if cfg.Secure != nil {
+		c.Secure = *cfg.Secure
+	}
+	switch strings.ToLower(cfg.SameSite) {
+	case "strict":
+		c.SameSite = http.SameSiteStrictMode
+	case "none":
+		// Trình duyệt bỏ qua SameSite=None nếu cookie không Secure
+		c.SameSite = http.SameSiteNoneMode
+		c.Secure = true
+	}
+	if maxAge < 0 {
+		c.MaxAge = -1
+	}
+	return c.String()
+}
+
+// setSessionCookies gắn cookie at/rt (HttpOnly) và csrf (client đọc được) vào header trả về
+func (a *authService) setSessionCookies(ctx context.Context, accessToken, refreshToken string) {
+	if !a.cookieMode() {
+		return
+	}
+	csrf, err := newCsrfToken()
+	if err != nil {
+		a.log.Error("Failed to generate csrf token: " + err.Error())
+		return
+	}
+	md := metadata.Pairs(
+		constants.HeaderSetCookie, a.newCookie(constants.KeyCookieAccessToken, accessToken, accessCookieMaxAge, true),
+		constants.HeaderSetCookie, a.newCookie(constants.KeyCookieRefreshToken, refreshToken, refreshCookieMaxAge, true),
+		constants.HeaderSetCookie, a.newCookie(constants.KeyCookieCsrfToken, csrf, refreshCookieMaxAge, false),
+	)
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		a.log.Error("Failed to set session cookies: " + err.Error())
+	}
+}
+
+func (a *authService) clearSessionCookies(ctx context.Context) {
+	if !a.cookieMode() {
+		return
+	}
+	md := metadata.Pairs(
+		constants.HeaderSetCookie, a.newCookie(constants.KeyCookieAccessToken, "", -1, true),
+		constants.HeaderSetCookie, a.newCookie(constants.KeyCookieRefreshToken, "", -1, true),
+		constants.HeaderSetCookie, a.newCookie(constants.KeyCookieCsrfToken, "", -1, false),
+	)
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		a.log.Error("Failed to clear session cookies: " + err.Error())
+	}
+}
+
+func newCsrfToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,69 @@
+package grpcservice
+
+import (
+	"auth-service/constants"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	headerAuthorization = "authorization"
+	bearerScheme        = "bearer"
+)
+
+// tokenExtractor lấy token của người gọi từ metadata theo thứ tự:
+// metadata key cấu hình riêng, Authorization: Bearer, rồi tới cookie.
+type tokenExtractor struct {
+	metadataKey string
+}
+
+func newTokenExtractor(metadataKey string) *tokenExtractor {
+	return &tokenExtractor{metadataKey: strings.ToLower(strings.TrimSpace(metadataKey))}
+}
+
+func (e *tokenExtractor) AccessToken(md metadata.MD) string {
+	if e.metadataKey != "" {
+		if vals := md.Get(e.metadataKey); len(vals) > 0 && strings.TrimSpace(vals[0]) != "" {
+			return strings.TrimSpace(vals[0])
+		}
+	}
+	if token := bearerToken(md); token != "" {
+		return token
+	}
+	return cookieValue(md, constants.KeyCookieAccessToken)
+}
+
+func (e *tokenExtractor) RefreshToken(md metadata.MD) string {
+	return cookieValue(md, constants.KeyCookieRefreshToken)
+}
+
+// bearerToken đọc "Authorization: Bearer <token>", scheme không phân biệt hoa thường
+func bearerToken(md metadata.MD) string {
+	for _, v := range md.Get(headerAuthorization) {
+		scheme, token, ok := strings.Cut(strings.TrimSpace(v), " ")
+		if !ok || !strings.EqualFold(scheme, bearerScheme) {
+			continue
+		}
+		if token = strings.TrimSpace(token); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// cookieValue phân tích header cookie theo RFC 6265: so khớp đúng tên cookie,
+// giữ nguyên giá trị chứa "=" và bỏ dấu nháy kép bao quanh.
+func cookieValue(md metadata.MD, name string) string {
+	vals := md.Get(constants.HeaderCookie)
+	if len(vals) == 0 {
+		return ""
+	}
+	r := http.Request{Header: http.Header{"Cookie": vals}}
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
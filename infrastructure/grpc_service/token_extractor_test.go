@@ -0,0 +1,132 @@
+package grpcservice
+
+import (
+	"auth-service/constants"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCookieValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		cookies []string
+		cookie  string
+		want    string
+	}{
+		{name: "single", cookies: []string{"at=abc"}, cookie: "at", want: "abc"},
+		{name: "among others", cookies: []string{"theme=dark; at=abc; lang=vi"}, cookie: "at", want: "abc"},
+		{name: "name is a prefix of another cookie", cookies: []string{"atx=wrong; at=abc"}, cookie: "at", want: "abc"},
+		{name: "another cookie ends with the name", cookies: []string{"xat=wrong; at=abc"}, cookie: "at", want: "abc"},
+		{name: "only a cookie sharing the prefix", cookies: []string{"att=wrong"}, cookie: "at", want: ""},
+		{name: "rt is not read as at", cookies: []string{"rt=refresh"}, cookie: "at", want: ""},
+		{name: "quoted value", cookies: []string{`at="abc"`}, cookie: "at", want: "abc"},
+		{name: "equals inside value", cookies: []string{"at=a=b=="}, cookie: "at", want: "a=b=="},
+		{name: "jwt with padding", cookies: []string{"at=eyJhbGciOiJIUzI1NiJ9.eyJpZCI6IjEifQ==.sig"}, cookie: "at", want: "eyJhbGciOiJIUzI1NiJ9.eyJpZCI6IjEifQ==.sig"},
+		{name: "duplicate cookie takes the first", cookies: []string{"at=first; at=second"}, cookie: "at", want: "first"},
+		{name: "duplicate across headers takes the first", cookies: []string{"at=first", "at=second"}, cookie: "at", want: "first"},
+		{name: "spaces around pairs", cookies: []string{"  lang=vi ;  at=abc  "}, cookie: "at", want: "abc"},
+		{name: "empty value", cookies: []string{"at="}, cookie: "at", want: ""},
+		{name: "name is case sensitive", cookies: []string{"AT=abc"}, cookie: "at", want: ""},
+		{name: "no cookie header", cookie: "at", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			for _, c := range tt.cookies {
+				md.Append(constants.HeaderCookie, c)
+			}
+			if got := cookieValue(md, tt.cookie); got != tt.want {
+				t.Fatalf("cookieValue(%q, %q) = %q, want %q", tt.cookies, tt.cookie, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadataKey string
+		md          metadata.MD
+		want        string
+	}{
+		{name: "bearer", md: metadata.Pairs("authorization", "Bearer abc"), want: "abc"},
+		{name: "bearer scheme is case insensitive", md: metadata.Pairs("authorization", "bEaReR abc"), want: "abc"},
+		{name: "bearer surrounding spaces", md: metadata.Pairs("authorization", "  Bearer   abc  "), want: "abc"},
+		{name: "basic is ignored", md: metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"), want: ""},
+		{name: "scheme without token", md: metadata.Pairs("authorization", "Bearer"), want: ""},
+		{name: "bearer with empty token", md: metadata.Pairs("authorization", "Bearer   "), want: ""},
+		{name: "bearer after other schemes", md: metadata.Pairs("authorization", "Basic x", "authorization", "Bearer abc"), want: "abc"},
+		{name: "cookie fallback", md: metadata.Pairs(constants.HeaderCookie, "at=cookie"), want: "cookie"},
+		{name: "bearer wins over cookie", md: metadata.Pairs("authorization", "Bearer abc", constants.HeaderCookie, "at=cookie"), want: "abc"},
+		{name: "metadata key wins", metadataKey: "X-Token", md: metadata.Pairs("x-token", " key ", "authorization", "Bearer abc"), want: "key"},
+		{name: "blank metadata key falls back", metadataKey: "x-token", md: metadata.Pairs("x-token", "  ", "authorization", "Bearer abc"), want: "abc"},
+		{name: "nothing", md: metadata.MD{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTokenExtractor(tt.metadataKey).AccessToken(tt.md); got != tt.want {
+				t.Fatalf("AccessToken = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenReadsOnlyCookie(t *testing.T) {
+	md := metadata.Pairs("authorization", "Bearer abc", constants.HeaderCookie, "at=access; rt=refresh")
+	if got := newTokenExtractor("").RefreshToken(md); got != "refresh" {
+		t.Fatalf("RefreshToken = %q, want %q", got, "refresh")
+	}
+}
+
+func FuzzCookieValue(f *testing.F) {
+	for _, seed := range []string{"at=abc", "atx=1; at=2", `at="q"`, "at=a=b", "at=1; at=2", ";;=;", "at", ""} {
+		f.Add(seed, "at")
+	}
+	f.Fuzz(func(t *testing.T, header, name string) {
+		got := cookieValue(metadata.Pairs(constants.HeaderCookie, header), name)
+		// giá trị trả về phải là một phần của header, không bao giờ chứa dấu phân cách cookie
+		if got != "" && (!strings.Contains(header, got) || strings.Contains(got, ";")) {
+			t.Fatalf("cookieValue(%q, %q) = %q", header, name, got)
+		}
+	})
+}
+
+func FuzzBearerToken(f *testing.F) {
+	for _, seed := range []string{"Bearer abc", "bearer  abc ", "Basic x", "Bearer", " ", "Bearer a b"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, header string) {
+		got := bearerToken(metadata.Pairs(headerAuthorization, header))
+		if got == "" {
+			return
+		}
+		if got != strings.TrimSpace(got) || !strings.Contains(header, got) {
+			t.Fatalf("bearerToken(%q) = %q", header, got)
+		}
+		scheme, _, _ := strings.Cut(strings.TrimSpace(header), " ")
+		if !strings.EqualFold(scheme, bearerScheme) {
+			t.Fatalf("bearerToken(%q) accepted scheme %q", header, scheme)
+		}
+	})
+}
+
+func FuzzAccessTokenMetadata(f *testing.F) {
+	f.Add("x-token", "abc", "Bearer def", "at=ghi")
+	f.Add("", "", "", "")
+	f.Add("X-Token", " ", "bearer x", "at=\"y\"")
+	f.Fuzz(func(t *testing.T, key, value, authorization, cookie string) {
+		md := metadata.MD{}
+		md.Append("x-token", value)
+		md.Append(headerAuthorization, authorization)
+		md.Append(constants.HeaderCookie, cookie)
+		got := newTokenExtractor(key).AccessToken(md)
+		if got == "" {
+			return
+		}
+		if !strings.Contains(value, got) && !strings.Contains(authorization, got) && !strings.Contains(cookie, got) {
+			t.Fatalf("AccessToken returned %q not present in metadata", got)
+		}
+	})
+}
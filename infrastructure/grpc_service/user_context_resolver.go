@@ -0,0 +1,143 @@
+package grpcservice
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"auth-service/domain/usecase"
+	"auth-service/infrastructure/grpc_client"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	"github.com/anhvanhoa/service-core/domain/log"
+	"github.com/anhvanhoa/service-core/domain/user_context"
+)
+
+const (
+	// personalAccessTokenContextTTL giới hạn thời gian quyền cũ còn hiệu lực sau khi đổi vai trò, khóa tài khoản
+	// hoặc vô hiệu hóa service account
+	personalAccessTokenContextTTL = time.Minute
+	personalAccessTokenTimeout    = 5 * time.Second
+)
+
+// UserContextResolver tìm UserContext cho token mà AuthorizationInterceptor nhận được.
+// Access token JWT được tra trong cache như lúc đăng nhập; personal access token được xác thực qua DB,
+// quyền lấy từ permission service rồi cắt theo scope của token và cache ngắn hạn theo hash của token.
+// Token của service account không có trong cache lúc cấp, quyền được lấy ở lần dùng đầu tiên và cache ngắn hạn;
+// UserContext của service account có FullName là tên và được đánh dấu bằng scope entity.PrincipalServiceAccount.
+type UserContextResolver struct {
+	cache            cache.CacheI
+	permissionCache  *PermissionCache
+	tokenUc          usecase.PersonalAccessTokenUsecase
+	serviceAccountUc usecase.ServiceAccountUsecase
+	accessTokens     repository.AccessTokenIssuer
+	log              *log.LogGRPCImpl
+}
+
+func NewUserContextResolver(
+	cache cache.CacheI,
+	permissionCache *PermissionCache,
+	tokenUc usecase.PersonalAccessTokenUsecase,
+	serviceAccountUc usecase.ServiceAccountUsecase,
+	accessTokens repository.AccessTokenIssuer,
+	log *log.LogGRPCImpl,
+) *UserContextResolver {
+	return &UserContextResolver{
+		cache:            cache,
+		permissionCache:  permissionCache,
+		tokenUc:          tokenUc,
+		serviceAccountUc: serviceAccountUc,
+		accessTokens:     accessTokens,
+		log:              log,
+	}
+}
+
+func (r *UserContextResolver) Resolve(at string) *user_context.UserContext {
+	key := at
+	if usecase.IsPersonalAccessToken(at) {
+		key = usecase.PersonalAccessTokenCacheKey(usecase.HashPersonalAccessToken(at))
+	}
+	if data, err := r.cache.Get(key); err == nil && data != nil {
+		uCtx := user_context.NewUserContext()
+		uCtx.FromBytes(data)
+		return uCtx
+	}
+	if key != at {
+		return r.resolvePersonalAccessToken(at, key)
+	}
+	if id, exp, ok := r.accessTokens.ServiceAccountOf(at); ok {
+		return r.resolveServiceAccount(at, id, exp)
+	}
+	return nil
+}
+
+func (r *UserContextResolver) resolvePersonalAccessToken(at, key string) *user_context.UserContext {
+	ctx, cancel := context.WithTimeout(context.Background(), personalAccessTokenTimeout)
+	defer cancel()
+	token, user, err := r.tokenUc.Authenticate(ctx, at)
+	if err != nil {
+		return nil
+	}
+	permissions, err := r.permissionCache.Resolve(ctx, grpc_client.PermissionRPCPersonalAccessToken, user.ID)
+	if err != nil {
+		r.log.Error("Failed to resolve permissions for personal access token: " + err.Error())
+		return nil
+	}
+	uCtx := scopeUserContext(convertPermissions(permissions.Permissions), token)
+	ttl := personalAccessTokenContextTTL
+	if token.ExpiresAt != nil {
+		ttl = min(ttl, time.Until(*token.ExpiresAt))
+	}
+	if bytes, err := uCtx.ToBytes(); err == nil {
+		r.cache.Set(key, bytes, ttl)
+	}
+	return uCtx
+}
+
+func (r *UserContextResolver) resolveServiceAccount(at, id string, exp time.Time) *user_context.UserContext {
+	ctx, cancel := context.WithTimeout(context.Background(), personalAccessTokenTimeout)
+	defer cancel()
+	account, err := r.serviceAccountUc.Authenticate(ctx, id)
+	if err != nil {
+		return nil
+	}
+	permissions, err := r.permissionCache.Resolve(ctx, grpc_client.PermissionRPCServiceAccount, account.ID)
+	if err != nil {
+		r.log.Error("Failed to resolve permissions for service account: " + err.Error())
+		return nil
+	}
+	uCtx := convertPermissions(permissions.Permissions)
+	uCtx.UserID = account.ID
+	uCtx.FullName = account.Name
+	uCtx.Email = account.Email()
+	uCtx.Scopes = append(uCtx.Scopes, serviceAccountScope(account))
+	if bytes, err := uCtx.ToBytes(); err == nil {
+		r.cache.Set(at, bytes, min(personalAccessTokenContextTTL, time.Until(exp)))
+	}
+	return uCtx
+}
+
+// serviceAccountScope là dấu hiệu service account trong UserContext, xem entity.PrincipalServiceAccount
+func serviceAccountScope(account entity.ServiceAccount) user_context.Scope {
+	data := map[string]string{entity.ServiceAccountScopeID: account.ID}
+	if account.OwnerOrganizationID != "" {
+		data[entity.ServiceAccountScopeOrganizationID] = account.OwnerOrganizationID
+	}
+	return user_context.Scope{
+		Resource:     entity.PrincipalScopeResource,
+		ResourceData: data,
+		Action:       entity.PrincipalServiceAccount,
+	}
+}
+
+// scopeUserContext chỉ giữ các quyền của user nằm trong scope của token
+func scopeUserContext(uCtx *user_context.UserContext, token entity.PersonalAccessToken) *user_context.UserContext {
+	uCtx.Permissions = slices.DeleteFunc(uCtx.Permissions, func(p user_context.Permission) bool {
+		return !token.Allows(p.Resource, p.Action)
+	})
+	uCtx.Scopes = slices.DeleteFunc(uCtx.Scopes, func(s user_context.Scope) bool {
+		return !token.Allows(s.Resource, s.Action)
+	})
+	return uCtx
+}
@@ -1,22 +1,29 @@
 package grpcservice
 
 import (
+	"auth-service/domain/entity"
+	"auth-service/infrastructure/metrics"
 	"context"
+	"time"
 
 	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func (a *authService) VerifyAccount(ctx context.Context, req *proto_auth.VerifyAccountRequest) (*proto_auth.VerifyAccountResponse, error) {
+func (a *authService) VerifyAccount(ctx context.Context, req *proto_auth.VerifyAccountRequest) (res *proto_auth.VerifyAccountResponse, err error) {
+	event := a.newAuthEvent(ctx, entity.AuthEventVerifyAccount, "")
+	defer func() { a.recordAuthEvent(event, err) }()
+
 	// Verify register token
 	claims, err := a.verifyAccountUc.VerifyRegister(req.GetToken())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "Token không hợp lệ hoặc đã hết hạn")
 	}
+	event.UserID = claims.Data.Id
 
 	// Get user by ID
-	_, err = a.verifyAccountUc.GetUserById(claims.Data.Id)
+	user, err := a.verifyAccountUc.GetUserById(claims.Data.Id)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, "Không tìm thấy người dùng")
 	}
@@ -25,6 +32,9 @@ func (a *authService) VerifyAccount(ctx context.Context, req *proto_auth.VerifyA
 	if err := a.verifyAccountUc.VerifyAccount(claims.Data.Id); err != nil {
 		return nil, status.Error(codes.Internal, "Không thể xác thực tài khoản")
 	}
+	if user.Veryfied == nil && user.RegisteredAt != nil {
+		metrics.ObserveRegistrationVerified(time.Since(*user.RegisteredAt))
+	}
 
 	return &proto_auth.VerifyAccountResponse{
 		Message: "Xác thực tài khoản thành công",
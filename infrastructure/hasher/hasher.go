@@ -0,0 +1,107 @@
+package hasher
+
+import (
+	"auth-service/bootstrap"
+	"auth-service/constants"
+	"auth-service/domain/repository"
+	"errors"
+
+	"github.com/alexedwards/argon2id"
+)
+
+var (
+	ErrUnknownHashFormat = errors.New("hasher: không nhận diện được định dạng mật khẩu")
+)
+
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// ParamsFromEnv đọc tham số Argon2id từ cấu hình, thiếu block argon2 thì dùng mặc định
+func ParamsFromEnv(env *bootstrap.Env) Params {
+	if env.Argon2 == nil {
+		return Params{}
+	}
+	return Params{
+		Memory:      env.Argon2.Memory,
+		Iterations:  env.Argon2.Iterations,
+		Parallelism: env.Argon2.Parallelism,
+		SaltLength:  env.Argon2.SaltLength,
+		KeyLength:   env.Argon2.KeyLength,
+	}
+}
+
+// Verifier kiểm tra mật khẩu cho một thuật toán, được chọn theo tiền tố của chuỗi hash.
+type Verifier interface {
+	Match(hash string) bool
+	Verify(hash, password string) (bool, error)
+}
+
+type hasher struct {
+	params    *argon2id.Params
+	verifiers []Verifier
+}
+
+// NewHasher tạo mật khẩu mới bằng Argon2id với tham số hiện tại và vẫn xác
+// thực được các hash cũ (bcrypt, PBKDF2 hoặc Argon2id với tham số khác).
+func NewHasher(params Params) repository.PasswordHasher {
+	p := &argon2id.Params{
+		Memory:      64 * 1024,
+		Iterations:  1,
+		Parallelism: 2,
+		SaltLength:  constants.SaltLength,
+		KeyLength:   32,
+	}
+	if params.Memory > 0 {
+		p.Memory = params.Memory
+	}
+	if params.Iterations > 0 {
+		p.Iterations = params.Iterations
+	}
+	if params.Parallelism > 0 {
+		p.Parallelism = params.Parallelism
+	}
+	if params.SaltLength > 0 {
+		p.SaltLength = params.SaltLength
+	}
+	if params.KeyLength > 0 {
+		p.KeyLength = params.KeyLength
+	}
+	return &hasher{
+		params: p,
+		verifiers: []Verifier{
+			&argon2Verifier{},
+			&bcryptVerifier{},
+			&pbkdf2Verifier{},
+		},
+	}
+}
+
+func (h *hasher) HashPassword(password string) (string, error) {
+	return argon2id.CreateHash(password, h.params)
+}
+
+func (h *hasher) VerifyPassword(hash, password string) (bool, error) {
+	for _, v := range h.verifiers {
+		if v.Match(hash) {
+			return v.Verify(hash, password)
+		}
+	}
+	return false, ErrUnknownHashFormat
+}
+
+func (h *hasher) NeedsRehash(hash string) bool {
+	params, salt, _, err := argon2id.DecodeHash(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.params.Memory ||
+		params.Iterations != h.params.Iterations ||
+		params.Parallelism != h.params.Parallelism ||
+		params.KeyLength != h.params.KeyLength ||
+		uint32(len(salt)) != h.params.SaltLength
+}
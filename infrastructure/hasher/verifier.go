@@ -0,0 +1,82 @@
+package hasher
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/alexedwards/argon2id"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type argon2Verifier struct{}
+
+func (v *argon2Verifier) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func (v *argon2Verifier) Verify(hash, password string) (bool, error) {
+	return argon2id.ComparePasswordAndHash(password, hash)
+}
+
+type bcryptVerifier struct{}
+
+func (v *bcryptVerifier) Match(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") ||
+		strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
+
+func (v *bcryptVerifier) Verify(hash, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// pbkdf2Verifier hỗ trợ định dạng "pbkdf2_<digest>$<iterations>$<salt>$<base64 key>".
+type pbkdf2Verifier struct{}
+
+var pbkdf2Digests = map[string]func() hash.Hash{
+	"pbkdf2_sha1":   sha1.New,
+	"pbkdf2_sha256": sha256.New,
+	"pbkdf2_sha512": sha512.New,
+}
+
+func (v *pbkdf2Verifier) Match(hash string) bool {
+	algorithm, _, _ := strings.Cut(hash, "$")
+	_, ok := pbkdf2Digests[algorithm]
+	return ok
+}
+
+func (v *pbkdf2Verifier) Verify(hash, password string) (bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 {
+		return false, ErrUnknownHashFormat
+	}
+	digest, ok := pbkdf2Digests[parts[0]]
+	if !ok {
+		return false, ErrUnknownHashFormat
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false, ErrUnknownHashFormat
+	}
+	expected, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, ErrUnknownHashFormat
+	}
+	key, err := pbkdf2.Key(digest, password, []byte(parts[2]), iterations, len(expected))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
@@ -0,0 +1,171 @@
+package health
+
+import (
+	"auth-service/bootstrap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	ComponentPostgres   = "postgres"
+	ComponentCache      = "cache"
+	ComponentQueue      = "queue"
+	ComponentMail       = "mail"
+	ComponentPermission = "permission"
+)
+
+// Các service con cho phép báo trạng thái suy giảm, vd. đăng nhập vẫn chạy khi mail service lỗi
+const (
+	ServiceLogin         = "auth.login"
+	ServiceRegister      = "auth.register"
+	ServicePasswordReset = "auth.password_reset"
+)
+
+const (
+	defaultInterval = 20 * time.Second
+	defaultTimeout  = 5 * time.Second
+)
+
+var ErrProbeTimeout = errors.New("health probe timed out")
+
+type Probe func(ctx context.Context) error
+
+type Checker struct {
+	server   *grpchealth.Server
+	log      *log.LogGRPCImpl
+	interval time.Duration
+	timeout  time.Duration
+	probes   map[string]Probe
+	services map[string][]string
+	mu       sync.RWMutex
+	up       map[string]bool
+}
+
+func NewChecker(env *bootstrap.Env, log *log.LogGRPCImpl) *Checker {
+	return &Checker{
+		server:   grpchealth.NewServer(),
+		log:      log,
+		interval: parseDuration(env.IntervalCheck, defaultInterval),
+		timeout:  parseDuration(env.TimeoutCheck, defaultTimeout),
+		probes:   make(map[string]Probe),
+		services: map[string][]string{
+			"":                   {ComponentPostgres, ComponentCache},
+			ServiceLogin:         {ComponentPostgres, ComponentCache, ComponentPermission},
+			ServiceRegister:      {ComponentPostgres, ComponentCache, ComponentQueue, ComponentMail},
+			ServicePasswordReset: {ComponentPostgres, ComponentCache, ComponentQueue, ComponentMail},
+		},
+		up: make(map[string]bool),
+	}
+}
+
+func parseDuration(s string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+func (c *Checker) AddProbe(component string, probe Probe) {
+	c.probes[component] = probe
+}
+
+// Register đăng ký grpc.health.v1.Health lên server
+func (c *Checker) Register(server *grpc.Server) {
+	healthpb.RegisterHealthServer(server, c.server)
+}
+
+// Start chạy kiểm tra một lần trước khi server nhận request, sau đó lặp theo interval_check
+func (c *Checker) Start(ctx context.Context) {
+	c.check(ctx)
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				c.server.Shutdown()
+				return
+			case <-ticker.C:
+				c.check(ctx)
+			}
+		}
+	}()
+}
+
+// Shutdown chuyển mọi service sang NOT_SERVING để load balancer ngừng gửi request mới khi service đang tắt
+func (c *Checker) Shutdown() {
+	c.server.Shutdown()
+}
+
+// Serving cho biết service con có đủ phụ thuộc để phục vụ không, checker nil coi như luôn sẵn sàng
+func (c *Checker) Serving(service string) bool {
+	if c == nil {
+		return true
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.serving(service)
+}
+
+func (c *Checker) serving(service string) bool {
+	for _, component := range c.services[service] {
+		if probe, ok := c.probes[component]; ok && probe != nil && !c.up[component] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Checker) check(ctx context.Context) {
+	up := make(map[string]bool, len(c.probes))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for component, probe := range c.probes {
+		wg.Add(1)
+		go func(component string, probe Probe) {
+			defer wg.Done()
+			err := c.run(ctx, probe)
+			if err != nil {
+				c.log.Error(fmt.Sprintf("Health check %s failed: %s", component, err.Error()))
+			}
+			mu.Lock()
+			up[component] = err == nil
+			mu.Unlock()
+		}(component, probe)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.up = up
+	for service := range c.services {
+		status := healthpb.HealthCheckResponse_SERVING
+		if !c.serving(service) {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		c.server.SetServingStatus(service, status)
+	}
+}
+
+// run giới hạn thời gian của probe theo timeout_check, kể cả probe không tôn trọng ctx
+func (c *Checker) run(ctx context.Context, probe Probe) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- probe(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ErrProbeTimeout
+	}
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	gc "github.com/anhvanhoa/service-core/domain/grpc_client"
+	"github.com/go-pg/pg/v10"
+	"github.com/hibiken/asynq"
+	"google.golang.org/grpc/connectivity"
+)
+
+const cacheProbeKey = "health:probe"
+
+var (
+	ErrClientNotConfigured = errors.New("grpc client not configured")
+	ErrClientUnavailable   = errors.New("grpc client unavailable")
+	ErrCacheMismatch       = errors.New("cache returned unexpected value")
+)
+
+func PostgresProbe(db *pg.DB) Probe {
+	return func(ctx context.Context) error {
+		return db.Ping(ctx)
+	}
+}
+
+func CacheProbe(c cache.CacheI) Probe {
+	return func(ctx context.Context) error {
+		value := []byte(time.Now().String())
+		if err := c.Set(cacheProbeKey, value, time.Minute); err != nil {
+			return err
+		}
+		got, err := c.Get(cacheProbeKey)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got, value) {
+			return ErrCacheMismatch
+		}
+		return nil
+	}
+}
+
+func QueueProbe(client *asynq.Client) Probe {
+	return func(ctx context.Context) error {
+		return client.Ping()
+	}
+}
+
+// GrpcClientProbe dựa vào trạng thái kết nối, kết nối idle được kích hoạt để lần kiểm tra sau có kết quả
+func GrpcClientProbe(client *gc.Client) Probe {
+	return func(ctx context.Context) error {
+		if client == nil {
+			return ErrClientNotConfigured
+		}
+		conn := client.GetConnection()
+		if conn == nil {
+			return ErrClientNotConfigured
+		}
+		switch conn.GetState() {
+		case connectivity.Idle:
+			conn.Connect()
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return ErrClientUnavailable
+		}
+		return nil
+	}
+}
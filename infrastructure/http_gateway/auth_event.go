@@ -0,0 +1,63 @@
+package httpgateway
+
+import (
+	"auth-service/domain/entity"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type authEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	Os        string    `json:"os"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newAuthEvent(e entity.AuthEvent) authEvent {
+	return authEvent{
+		ID:        e.ID,
+		Type:      string(e.Type),
+		IP:        e.IP,
+		UserAgent: e.UserAgent,
+		Os:        e.Os,
+		Outcome:   string(e.Outcome),
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+type listAuthEventsResponse struct {
+	AuthEvents []authEvent `json:"authEvents"`
+	Total      int         `json:"total"`
+}
+
+// handleListAuthEvents trả lịch sử đăng nhập của chính người gọi.
+// Người dùng lấy từ token, tham số user trên query (nếu có) bị bỏ qua.
+func (g *Gateway) handleListAuthEvents(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	pageSize, _ := strconv.Atoi(q.Get("pageSize"))
+	events, total, err := g.authEvents.ListByUser(r.Context(), caller.UserID, page, pageSize)
+	if err != nil {
+		g.writeError(w, r, err, nil, "Không thể lấy lịch sử đăng nhập")
+		return
+	}
+	res := listAuthEventsResponse{
+		AuthEvents: make([]authEvent, len(events)),
+		Total:      total,
+	}
+	for i, e := range events {
+		res.AuthEvents[i] = newAuthEvent(e)
+	}
+	writeJSON(w, res)
+}
@@ -0,0 +1,289 @@
+package httpgateway
+
+import (
+	"auth-service/bootstrap"
+	"auth-service/constants"
+	"auth-service/domain/usecase"
+	grpcservice "auth-service/infrastructure/grpc_service"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+	proto_auth "github.com/anhvanhoa/sf-proto/gen/auth/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:embed openapi.json
+var openAPIDoc []byte
+
+const shutdownTimeout = 10 * time.Second
+
+type Gateway struct {
+	env            *bootstrap.Env
+	log            *log.LogGRPCImpl
+	mux            *runtime.ServeMux
+	conn           *grpc.ClientConn
+	client         proto_auth.AuthServiceClient
+	notMe          usecase.NotMeUsecase
+	invitation     usecase.InvitationUsecase
+	serviceAccount usecase.ServiceAccountUsecase
+	callers        *grpcservice.CallerAuthenticator
+	tokens         usecase.PersonalAccessTokenUsecase
+	organization   usecase.OrganizationUsecase
+	authEvents     usecase.AuthEventUsecase
+}
+
+// NewGateway tạo HTTP server REST/JSON chuyển tiếp mọi RPC của AuthService tới gRPC server local,
+// nhờ vậy request HTTP đi qua cùng interceptor với request gRPC.
+// Các luồng chưa có RPC trong proto được đăng ký bằng HandlePath và gọi thẳng usecase.
+func NewGateway(
+	env *bootstrap.Env,
+	log *log.LogGRPCImpl,
+	notMe usecase.NotMeUsecase,
+	invitation usecase.InvitationUsecase,
+	serviceAccount usecase.ServiceAccountUsecase,
+	callers *grpcservice.CallerAuthenticator,
+	tokens usecase.PersonalAccessTokenUsecase,
+	organization usecase.OrganizationUsecase,
+	authEvents usecase.AuthEventUsecase,
+) (*Gateway, error) {
+	conn, err := grpc.NewClient(
+		fmt.Sprintf("%s:%d", env.HostGrpc, env.PortGrpc),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	g := &Gateway{
+		env:            env,
+		log:            log,
+		conn:           conn,
+		client:         proto_auth.NewAuthServiceClient(conn),
+		notMe:          notMe,
+		invitation:     invitation,
+		serviceAccount: serviceAccount,
+		callers:        callers,
+		tokens:         tokens,
+		organization:   organization,
+		authEvents:     authEvents,
+	}
+	g.mux = runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
+	if err := g.registerRoutes(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return g, nil
+}
+
+// headerMatcher giữ nguyên tên Cookie, Authorization và X-Csrf-Token để handler đọc được như khi gọi gRPC trực tiếp
+func headerMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case "Cookie":
+		return "cookie", true
+	case "Authorization":
+		return "authorization", true
+	case "X-Csrf-Token":
+		return constants.HeaderCsrfToken, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher chuyển set-cookie trong metadata thành Set-Cookie của HTTP và giữ nguyên tên header
+// mật khẩu hết hạn, các header khác giữ tiền tố Grpc-Metadata- như mặc định
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case constants.HeaderSetCookie:
+		return "Set-Cookie", true
+	case constants.HeaderPasswordExpired, constants.HeaderPasswordChangeToken:
+		return textproto.CanonicalMIMEHeaderKey(key), true
+	}
+	return fmt.Sprintf("%s%s", runtime.MetadataHeaderPrefix, key), true
+}
+
+func (g *Gateway) registerRoutes() error {
+	c := g.client
+	routes := []error{
+		handle(g, http.MethodPost, "/v1/auth/register", c.Register),
+		handle(g, http.MethodPost, "/v1/auth/login", c.Login),
+		handle(g, http.MethodPost, "/v1/auth/logout", c.Logout),
+		handle(g, http.MethodPost, "/v1/auth/refresh-token", c.RefreshToken),
+		handle(g, http.MethodPost, "/v1/auth/verify-account", c.VerifyAccount),
+		handle(g, http.MethodPost, "/v1/auth/forgot-password", c.ForgotPassword),
+		handle(g, http.MethodPost, "/v1/auth/reset-password/token", c.ResetPasswordByToken),
+		handle(g, http.MethodPost, "/v1/auth/reset-password/code", c.ResetPasswordByCode),
+		handle(g, http.MethodPost, "/v1/auth/check-token", c.CheckToken),
+		handle(g, http.MethodPost, "/v1/auth/check-code", c.CheckCode),
+		handle(g, http.MethodGet, "/v1/auth/profile", c.Profile),
+		g.mux.HandlePath(http.MethodPost, "/v1/auth/not-me", g.handleNotMe),
+		g.mux.HandlePath(http.MethodPost, "/v1/auth/invitations/accept", g.handleAcceptInvitation),
+		g.mux.HandlePath(http.MethodPost, "/v1/auth/service-accounts/token", g.handleServiceAccountToken),
+		g.mux.HandlePath(http.MethodGet, "/v1/auth/personal-access-tokens", g.handleListPersonalAccessTokens),
+		g.mux.HandlePath(http.MethodPost, "/v1/auth/personal-access-tokens", g.handleCreatePersonalAccessToken),
+		g.mux.HandlePath(http.MethodDelete, "/v1/auth/personal-access-tokens/{id}", g.handleRevokePersonalAccessToken),
+		g.mux.HandlePath(http.MethodGet, "/v1/auth/organizations", g.handleListOrganizations),
+		g.mux.HandlePath(http.MethodPost, "/v1/auth/organizations", g.handleCreateOrganization),
+		g.mux.HandlePath(http.MethodPost, "/v1/auth/organizations/active", g.handleSwitchOrganization),
+		g.mux.HandlePath(http.MethodPost, "/v1/auth/organizations/{id}/members", g.handleAddOrganizationMember),
+		g.mux.HandlePath(http.MethodGet, "/v1/auth/auth-events", g.handleListAuthEvents),
+		g.mux.HandlePath(http.MethodGet, "/openapi.json", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(openAPIDoc)
+		}),
+	}
+	return errors.Join(routes...)
+}
+
+type notMeRequest struct {
+	Token string `json:"token"`
+	Os    string `json:"os"`
+}
+
+type notMeResponse struct {
+	Token   string `json:"token"`
+	Message string `json:"message"`
+}
+
+// handleNotMe xử lý link "không phải tôi" trong mail cảnh báo thiết bị lạ. Chưa có RPC tương ứng trong proto
+// nên route gọi thẳng usecase; token trả về dùng với /v1/auth/reset-password/token.
+func (g *Gateway) handleNotMe(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req notMeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		g.writeError(w, r, status.Error(codes.InvalidArgument, "Thiếu token"), nil, "")
+		return
+	}
+	token, err := g.notMe.Confirm(r.Context(), req.Token, req.Os)
+	if err != nil {
+		g.writeError(w, r, err, errorCodes{
+			usecase.ErrNotMeTokenInvalid: codes.InvalidArgument,
+		}, "Không thể thu hồi phiên đăng nhập")
+		return
+	}
+	writeJSON(w, notMeResponse{
+		Token:   token,
+		Message: "Đã thu hồi phiên đăng nhập, vui lòng đặt lại mật khẩu",
+	})
+}
+
+// caller xác thực người gọi của các route tự quản lý tài khoản. Token được đọc từ Authorization hoặc cookie như RPC,
+// chỉ chấp nhận phiên đăng nhập để personal access token hay service account không tự cấp thêm quyền cho mình.
+func (g *Gateway) caller(r *http.Request) (grpcservice.Caller, error) {
+	ctx, err := runtime.AnnotateContext(r.Context(), g.mux, r, r.URL.Path)
+	if err != nil {
+		return grpcservice.Caller{}, err
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	caller, err := g.callers.Authenticate(r.Method, md)
+	if err != nil {
+		return grpcservice.Caller{}, err
+	}
+	if !caller.Session {
+		return grpcservice.Caller{}, status.Error(codes.PermissionDenied, "Thao tác này cần phiên đăng nhập của người dùng")
+	}
+	return caller, nil
+}
+
+// errorCodes gán code cho các lỗi nghiệp vụ được trả nguyên văn cho client
+type errorCodes map[error]codes.Code
+
+// writeError trả lỗi theo định dạng của grpc-gateway. Lỗi status giữ nguyên, lỗi có trong known dùng code tương ứng,
+// lỗi còn lại được log và trả Internal với thông báo internal.
+func (g *Gateway) writeError(w http.ResponseWriter, r *http.Request, err error, known errorCodes, internal string) {
+	_, outbound := runtime.MarshalerForRequest(g.mux, r)
+	if _, ok := status.FromError(err); !ok {
+		code, found := codes.Internal, false
+		for target, c := range known {
+			if errors.Is(err, target) {
+				code, found = c, true
+				break
+			}
+		}
+		if found {
+			err = status.Error(code, err.Error())
+		} else {
+			g.log.Error(fmt.Sprintf("%s %s: %s", r.Method, r.URL.Path, err.Error()))
+			err = status.Error(codes.Internal, internal)
+		}
+	}
+	runtime.HTTPError(r.Context(), g.mux, outbound, w, r, err)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+type rpcCall[Req, Res proto.Message] func(ctx context.Context, in Req, opts ...grpc.CallOption) (Res, error)
+
+// handle đăng ký một route: decode JSON body vào request, gọi RPC rồi trả response theo định dạng của grpc-gateway
+func handle[Req, Res proto.Message](
+	g *Gateway,
+	method, path string,
+	call rpcCall[Req, Res],
+) error {
+	return g.mux.HandlePath(method, path, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		inbound, outbound := runtime.MarshalerForRequest(g.mux, r)
+		ctx, err := runtime.AnnotateContext(r.Context(), g.mux, r, path)
+		if err != nil {
+			runtime.HTTPError(r.Context(), g.mux, outbound, w, r, err)
+			return
+		}
+
+		req := newMessage[Req]()
+		if r.Method != http.MethodGet {
+			if err := inbound.NewDecoder(r.Body).Decode(req); err != nil && !errors.Is(err, io.EOF) {
+				runtime.HTTPError(ctx, g.mux, outbound, w, r, err)
+				return
+			}
+		}
+
+		var header, trailer metadata.MD
+		res, err := call(ctx, req, grpc.Header(&header), grpc.Trailer(&trailer))
+		ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: header, TrailerMD: trailer})
+		if err != nil {
+			runtime.HTTPError(ctx, g.mux, outbound, w, r, err)
+			return
+		}
+		runtime.ForwardResponseMessage(ctx, g.mux, outbound, w, r, res)
+	})
+}
+
+func newMessage[T proto.Message]() T {
+	var zero T
+	return zero.ProtoReflect().Type().New().Interface().(T)
+}
+
+func (g *Gateway) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", g.env.HttpGateway.Port),
+		Handler:           g.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+		g.conn.Close()
+	}()
+	g.log.Info(fmt.Sprintf("HTTP gateway listening on %s", srv.Addr))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package httpgateway
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/usecase"
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type acceptInvitationRequest struct {
+	Token           string `json:"token"`
+	FullName        string `json:"fullName"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+type acceptInvitationResponse struct {
+	User    userInfo `json:"user"`
+	Message string   `json:"message"`
+}
+
+type userInfo struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	FullName string `json:"fullName"`
+}
+
+func newUserInfo(u entity.UserInfor) userInfo {
+	return userInfo{ID: u.ID, Email: u.Email, FullName: u.FullName}
+}
+
+// handleAcceptInvitation được gọi từ trang FRONTEND_URL/auth/invitation/<token>: người được mời đặt mật khẩu
+// và kích hoạt tài khoản, sau đó đăng nhập như bình thường qua /v1/auth/login
+func (g *Gateway) handleAcceptInvitation(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req acceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		g.writeError(w, r, status.Error(codes.InvalidArgument, "Thiếu token"), nil, "")
+		return
+	}
+	user, err := g.invitation.AcceptInvitation(r.Context(), usecase.AcceptInvitationReq{
+		Token:           req.Token,
+		FullName:        req.FullName,
+		Password:        req.Password,
+		ConfirmPassword: req.ConfirmPassword,
+	})
+	if err != nil {
+		g.writeError(w, r, err, errorCodes{
+			usecase.ErrInvitationNoPassword: codes.InvalidArgument,
+			usecase.ErrInvitationPassword:   codes.InvalidArgument,
+			usecase.ErrPasswordBreached:     codes.InvalidArgument,
+			usecase.ErrInvitationInvalid:    codes.NotFound,
+			usecase.ErrInvitationAccepted:   codes.FailedPrecondition,
+		}, "Không thể kích hoạt tài khoản")
+		return
+	}
+	writeJSON(w, acceptInvitationResponse{
+		User:    newUserInfo(user),
+		Message: "Kích hoạt tài khoản thành công, vui lòng đăng nhập",
+	})
+}
@@ -0,0 +1,169 @@
+package httpgateway
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/usecase"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type organizationMember struct {
+	OrganizationID string    `json:"organizationId"`
+	UserID         string    `json:"userId"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+func newOrganizationMember(m entity.OrganizationMember) organizationMember {
+	return organizationMember{
+		OrganizationID: m.OrganizationID,
+		UserID:         m.UserID,
+		Role:           string(m.Role),
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+type listOrganizationsResponse struct {
+	ActiveOrganizationID string               `json:"activeOrganizationId"`
+	Memberships          []organizationMember `json:"memberships"`
+}
+
+type createOrganizationRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type addOrganizationMemberRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type switchOrganizationRequest struct {
+	OrganizationID string `json:"organizationId"`
+}
+
+type switchOrganizationResponse struct {
+	ActiveOrganizationID string `json:"activeOrganizationId"`
+	Message              string `json:"message"`
+}
+
+var organizationErrors = errorCodes{
+	usecase.ErrOrganizationNotFound:      codes.NotFound,
+	usecase.ErrInvalidOrganizationName:   codes.InvalidArgument,
+	usecase.ErrInvalidOrganizationSlug:   codes.InvalidArgument,
+	usecase.ErrInvalidOrganizationRole:   codes.InvalidArgument,
+	usecase.ErrOrganizationNameExists:    codes.AlreadyExists,
+	usecase.ErrOrganizationSlugExists:    codes.AlreadyExists,
+	usecase.ErrAlreadyOrganizationMember: codes.AlreadyExists,
+	usecase.ErrNotOrganizationMember:     codes.PermissionDenied,
+	usecase.ErrOrganizationForbidden:     codes.PermissionDenied,
+	usecase.ErrSingleOrganization:        codes.FailedPrecondition,
+	usecase.ErrUserNotFound:              codes.NotFound,
+}
+
+// Các route tổ chức luôn thao tác với tư cách người gọi: người tạo là owner, thêm thành viên cần quyền trong tổ chức
+func (g *Gateway) handleListOrganizations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	memberships, err := g.organization.ListMemberships(r.Context(), caller.UserID)
+	if err != nil {
+		g.writeError(w, r, err, organizationErrors, "Không thể lấy danh sách tổ chức")
+		return
+	}
+	active, err := g.organization.ActiveOrganization(r.Context(), caller.UserID)
+	if err != nil {
+		g.writeError(w, r, err, organizationErrors, "Không thể lấy danh sách tổ chức")
+		return
+	}
+	res := listOrganizationsResponse{
+		ActiveOrganizationID: active,
+		Memberships:          make([]organizationMember, len(memberships)),
+	}
+	for i, m := range memberships {
+		res.Memberships[i] = newOrganizationMember(m)
+	}
+	writeJSON(w, res)
+}
+
+func (g *Gateway) handleCreateOrganization(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	var req createOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		g.writeError(w, r, status.Error(codes.InvalidArgument, "Body không hợp lệ"), nil, "")
+		return
+	}
+	org, err := g.organization.Create(r.Context(), usecase.CreateOrganizationReq{
+		Name:    req.Name,
+		Slug:    req.Slug,
+		OwnerID: caller.UserID,
+	})
+	if err != nil {
+		g.writeError(w, r, err, organizationErrors, "Không thể tạo tổ chức")
+		return
+	}
+	writeJSON(w, organization{ID: org.ID, Name: org.Name, Slug: org.Slug, CreatedAt: org.CreatedAt})
+}
+
+func (g *Gateway) handleAddOrganizationMember(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	var req addOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		g.writeError(w, r, status.Error(codes.InvalidArgument, "Thiếu email"), nil, "")
+		return
+	}
+	member, err := g.organization.AddMember(r.Context(), usecase.AddOrganizationMemberReq{
+		OrganizationID: params["id"],
+		ActorID:        caller.UserID,
+		Email:          req.Email,
+		Role:           entity.OrganizationRole(req.Role),
+	})
+	if err != nil {
+		g.writeError(w, r, err, organizationErrors, "Không thể thêm thành viên")
+		return
+	}
+	writeJSON(w, newOrganizationMember(member))
+}
+
+// handleSwitchOrganization đổi tổ chức hoạt động; claim org trong access token chỉ đổi sau khi gọi /v1/auth/refresh-token
+func (g *Gateway) handleSwitchOrganization(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	var req switchOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.OrganizationID == "" {
+		g.writeError(w, r, status.Error(codes.InvalidArgument, "Thiếu tổ chức"), nil, "")
+		return
+	}
+	if err := g.organization.SwitchActive(r.Context(), caller.UserID, req.OrganizationID); err != nil {
+		g.writeError(w, r, err, organizationErrors, "Không thể đổi tổ chức")
+		return
+	}
+	writeJSON(w, switchOrganizationResponse{
+		ActiveOrganizationID: req.OrganizationID,
+		Message:              "Đã đổi tổ chức, làm mới token để áp dụng",
+	})
+}
@@ -0,0 +1,119 @@
+package httpgateway
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/usecase"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type personalAccessToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func newPersonalAccessToken(t entity.PersonalAccessToken) personalAccessToken {
+	return personalAccessToken{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.Scopes,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		RevokedAt:  t.RevokedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+type createPersonalAccessTokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type createPersonalAccessTokenResponse struct {
+	PersonalAccessToken personalAccessToken `json:"personalAccessToken"`
+	// Token chỉ trả về một lần lúc tạo
+	Token string `json:"token"`
+}
+
+type listPersonalAccessTokensResponse struct {
+	PersonalAccessTokens []personalAccessToken `json:"personalAccessTokens"`
+}
+
+var personalAccessTokenErrors = errorCodes{
+	usecase.ErrInvalidTokenName:            codes.InvalidArgument,
+	usecase.ErrTokenNameExists:             codes.AlreadyExists,
+	usecase.ErrInvalidTokenScope:           codes.InvalidArgument,
+	usecase.ErrInvalidTokenExpiry:          codes.InvalidArgument,
+	usecase.ErrPersonalAccessTokenNotFound: codes.NotFound,
+}
+
+// Các route personal access token luôn thao tác trên token của chính người gọi
+func (g *Gateway) handleListPersonalAccessTokens(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	tokens, err := g.tokens.List(r.Context(), caller.UserID)
+	if err != nil {
+		g.writeError(w, r, err, personalAccessTokenErrors, "Không thể lấy danh sách token")
+		return
+	}
+	res := listPersonalAccessTokensResponse{PersonalAccessTokens: make([]personalAccessToken, len(tokens))}
+	for i, t := range tokens {
+		res.PersonalAccessTokens[i] = newPersonalAccessToken(t)
+	}
+	writeJSON(w, res)
+}
+
+func (g *Gateway) handleCreatePersonalAccessToken(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	var req createPersonalAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		g.writeError(w, r, status.Error(codes.InvalidArgument, "Body không hợp lệ"), nil, "")
+		return
+	}
+	pat, token, err := g.tokens.Create(r.Context(), usecase.CreatePersonalAccessTokenReq{
+		UserID:    caller.UserID,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		g.writeError(w, r, err, personalAccessTokenErrors, "Không thể tạo token")
+		return
+	}
+	writeJSON(w, createPersonalAccessTokenResponse{
+		PersonalAccessToken: newPersonalAccessToken(pat),
+		Token:               token,
+	})
+}
+
+func (g *Gateway) handleRevokePersonalAccessToken(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	caller, err := g.caller(r)
+	if err != nil {
+		g.writeError(w, r, err, nil, "")
+		return
+	}
+	if err := g.tokens.Revoke(r.Context(), caller.UserID, params["id"]); err != nil {
+		g.writeError(w, r, err, personalAccessTokenErrors, "Không thể thu hồi token")
+		return
+	}
+	writeJSON(w, struct{}{})
+}
@@ -0,0 +1,51 @@
+package httpgateway
+
+import (
+	"auth-service/domain/usecase"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serviceAccountTokenRequest struct {
+	KeyID     string `json:"keyId"`
+	Secret    string `json:"secret"`
+	Assertion string `json:"assertion"`
+}
+
+type serviceAccountTokenResponse struct {
+	AccessToken string    `json:"accessToken"`
+	TokenType   string    `json:"tokenType"`
+	ExpiresIn   int64     `json:"expiresIn"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// handleServiceAccountToken đổi secret hoặc client assertion của service account lấy access token.
+// Thông tin xác thực nằm trong body nên route không cần token người gọi.
+func (g *Gateway) handleServiceAccountToken(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req serviceAccountTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.Secret == "" && req.Assertion == "") {
+		g.writeError(w, r, status.Error(codes.InvalidArgument, "Thiếu secret hoặc assertion"), nil, "")
+		return
+	}
+	token, exp, err := g.serviceAccount.IssueToken(r.Context(), usecase.IssueServiceAccountTokenReq{
+		KeyID:     req.KeyID,
+		Secret:    req.Secret,
+		Assertion: req.Assertion,
+	})
+	if err != nil {
+		g.writeError(w, r, err, errorCodes{
+			usecase.ErrServiceAccountCredentials: codes.Unauthenticated,
+		}, "Không thể cấp token cho service account")
+		return
+	}
+	writeJSON(w, serviceAccountTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(exp).Seconds()),
+		ExpiresAt:   exp,
+	})
+}
@@ -0,0 +1,116 @@
+package job
+
+import (
+	"auth-service/domain/repository"
+	"auth-service/domain/usecase"
+	"auth-service/infrastructure/metrics"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	janitorLockName        = "auth-service:janitor"
+	defaultJanitorSchedule = "@every 10m"
+)
+
+type JanitorConfig struct {
+	Schedule       string
+	AuditRetention time.Duration
+}
+
+type JanitorJob struct {
+	config       JanitorConfig
+	locker       repository.AdvisoryLocker
+	janitorUc    usecase.JanitorUsecase
+	authEventUc  usecase.AuthEventUsecase
+	unverifiedUc usecase.UnverifiedAccountUsecase
+	log          *log.LogGRPCImpl
+}
+
+func NewJanitorJob(
+	config JanitorConfig,
+	locker repository.AdvisoryLocker,
+	janitorUc usecase.JanitorUsecase,
+	authEventUc usecase.AuthEventUsecase,
+	unverifiedUc usecase.UnverifiedAccountUsecase,
+	log *log.LogGRPCImpl,
+) *JanitorJob {
+	if config.Schedule == "" {
+		config.Schedule = defaultJanitorSchedule
+	}
+	return &JanitorJob{
+		config:       config,
+		locker:       locker,
+		janitorUc:    janitorUc,
+		authEventUc:  authEventUc,
+		unverifiedUc: unverifiedUc,
+		log:          log,
+	}
+}
+
+func (j *JanitorJob) Start(ctx context.Context, workers repository.WorkerGroup) {
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	if _, err := c.AddFunc(j.config.Schedule, func() { j.Run(ctx) }); err != nil {
+		j.log.Error("Invalid janitor schedule: " + err.Error())
+		return
+	}
+	c.Start()
+	workers.Go("janitor", func(context.Context) error {
+		<-ctx.Done()
+		// Stop trả về context xong khi lượt dọn dẹp đang chạy kết thúc
+		<-c.Stop().Done()
+		return nil
+	})
+}
+
+// Run chỉ dọn dẹp khi giữ được advisory lock, các replica khác bỏ qua lượt này
+func (j *JanitorJob) Run(ctx context.Context) {
+	ran, err := j.locker.TryWithLock(ctx, janitorLockName, func(ctx context.Context) error {
+		j.purge(ctx)
+		return nil
+	})
+	if err != nil {
+		j.log.Error("Failed to acquire janitor lock: " + err.Error())
+		return
+	}
+	if !ran {
+		j.log.Info("Janitor is running on another replica, skipping")
+	}
+}
+
+func (j *JanitorJob) purge(ctx context.Context) {
+	tasks := []struct {
+		name    string
+		run     func(ctx context.Context) (int, error)
+		observe func(n int)
+	}{
+		{"delete expired sessions", j.janitorUc.PurgeExpiredSessions, nil},
+		{"delete expired invitations", j.janitorUc.PurgeExpiredInvitations, nil},
+		{"delete auth events", func(ctx context.Context) (int, error) {
+			if j.config.AuditRetention <= 0 {
+				return 0, nil
+			}
+			return j.authEventUc.DeleteExpired(ctx, j.config.AuditRetention)
+		}, nil},
+		// Nhắc trước rồi mới xóa để tài khoản vừa được nhắc không bị xóa trong cùng lượt
+		{"send verify reminders", j.unverifiedUc.SendReminders, metrics.UnverifiedReminded},
+		{"delete unverified users", j.unverifiedUc.Purge, metrics.UnverifiedDeleted},
+	}
+	for _, task := range tasks {
+		n, err := task.run(ctx)
+		if task.observe != nil {
+			task.observe(n)
+		}
+		if err != nil {
+			j.log.Error(fmt.Sprintf("Janitor task %q failed: %s", task.name, err.Error()))
+			continue
+		}
+		if n > 0 {
+			j.log.Info(fmt.Sprintf("Janitor task %q processed %d rows", task.name, n))
+		}
+	}
+}
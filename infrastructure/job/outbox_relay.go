@@ -0,0 +1,73 @@
+package job
+
+import (
+	"auth-service/domain/repository"
+	"auth-service/domain/usecase"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+)
+
+const (
+	outboxRelayInterval = 2 * time.Second
+	outboxPruneInterval = time.Hour
+	outboxRetention     = 7 * 24 * time.Hour
+)
+
+type OutboxRelayJob struct {
+	relayUc usecase.OutboxRelayUsecase
+	log     *log.LogGRPCImpl
+}
+
+func NewOutboxRelayJob(relayUc usecase.OutboxRelayUsecase, log *log.LogGRPCImpl) *OutboxRelayJob {
+	return &OutboxRelayJob{
+		relayUc: relayUc,
+		log:     log,
+	}
+}
+
+func (j *OutboxRelayJob) Start(ctx context.Context, workers repository.WorkerGroup) {
+	workers.Go("outbox_relay", func(context.Context) error {
+		relay := time.NewTicker(outboxRelayInterval)
+		defer relay.Stop()
+		prune := time.NewTicker(outboxPruneInterval)
+		defer prune.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-relay.C:
+				j.Run(ctx)
+			case <-prune.C:
+				j.Prune(ctx)
+			}
+		}
+	})
+}
+
+// Run gửi hết các message đến hạn, dừng khi một lô không còn message nào
+func (j *OutboxRelayJob) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivered, err := j.relayUc.Relay(ctx)
+		if err != nil {
+			j.log.Error("Failed to relay outbox messages: " + err.Error())
+			return
+		}
+		if delivered == 0 {
+			return
+		}
+	}
+}
+
+func (j *OutboxRelayJob) Prune(ctx context.Context) {
+	deleted, err := j.relayUc.DeleteDelivered(ctx, outboxRetention)
+	if err != nil {
+		j.log.Error("Failed to delete delivered outbox messages: " + err.Error())
+		return
+	}
+	if deleted > 0 {
+		j.log.Info(fmt.Sprintf("Deleted %d delivered outbox messages", deleted))
+	}
+}
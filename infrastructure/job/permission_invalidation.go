@@ -0,0 +1,74 @@
+package job
+
+import (
+	"auth-service/bootstrap"
+	"auth-service/constants"
+	"auth-service/domain/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+	"github.com/hibiken/asynq"
+)
+
+type PermissionInvalidator interface {
+	InvalidateUser(ctx context.Context, userID string) (int, error)
+}
+
+type PermissionChangedPayload struct {
+	UserID string `json:"user_id"`
+}
+
+// PermissionInvalidationJob nhận task permission.user_changed từ permission service
+// và làm mới quyền đã cache cho mọi access token còn sống của user.
+type PermissionInvalidationJob struct {
+	server      *asynq.Server
+	invalidator PermissionInvalidator
+	log         *log.LogGRPCImpl
+}
+
+func NewPermissionInvalidationJob(env *bootstrap.Env, invalidator PermissionInvalidator, log *log.LogGRPCImpl) *PermissionInvalidationJob {
+	server := asynq.NewServer(asynq.RedisClientOpt{
+		Network:  env.Queue.Network,
+		Addr:     env.Queue.Addr,
+		Password: env.Queue.Password,
+		DB:       env.Queue.Db,
+	}, asynq.Config{
+		Concurrency: 2,
+		Queues:      map[string]int{string(constants.QUEUE_PERMISSION): 1},
+	})
+	return &PermissionInvalidationJob{
+		server:      server,
+		invalidator: invalidator,
+		log:         log,
+	}
+}
+
+func (j *PermissionInvalidationJob) Start(ctx context.Context, workers repository.WorkerGroup) {
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(constants.TASK_PERMISSION_USER_CHANGED, j.handle)
+	if err := j.server.Start(mux); err != nil {
+		j.log.Error("Failed to start permission invalidation consumer: " + err.Error())
+		return
+	}
+	workers.Go("permission_invalidation", func(context.Context) error {
+		<-ctx.Done()
+		// Shutdown chờ các task đang xử lý xong
+		j.server.Shutdown()
+		return nil
+	})
+}
+
+func (j *PermissionInvalidationJob) handle(ctx context.Context, task *asynq.Task) error {
+	var payload PermissionChangedPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil || payload.UserID == "" {
+		return fmt.Errorf("invalid payload: %w", asynq.SkipRetry)
+	}
+	affected, err := j.invalidator.InvalidateUser(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	j.log.Info(fmt.Sprintf("Refreshed permissions for %d token(s) of user %s", affected, payload.UserID))
+	return nil
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"auth-service/domain/repository"
+	"context"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+)
+
+const (
+	permissionRegisterMinBackoff = 2 * time.Second
+	permissionRegisterMaxBackoff = 5 * time.Minute
+)
+
+// PermissionRegistrationJob đăng ký quyền của service lên permission service,
+// thử lại với backoff cho tới khi thành công để service vẫn khởi động được khi permission service chưa sẵn sàng.
+type PermissionRegistrationJob struct {
+	register func(ctx context.Context) error
+	log      *log.LogGRPCImpl
+}
+
+func NewPermissionRegistrationJob(register func(ctx context.Context) error, log *log.LogGRPCImpl) *PermissionRegistrationJob {
+	return &PermissionRegistrationJob{
+		register: register,
+		log:      log,
+	}
+}
+
+func (j *PermissionRegistrationJob) Start(ctx context.Context, workers repository.WorkerGroup) {
+	workers.Go("permission_registration", func(context.Context) error {
+		backoff := permissionRegisterMinBackoff
+		for {
+			err := j.register(ctx)
+			if err == nil {
+				j.log.Info("Registered permissions")
+				return nil
+			}
+			j.log.Error("Failed to register permission, retrying in " + backoff.String() + ": " + err.Error())
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, permissionRegisterMaxBackoff)
+		}
+	})
+}
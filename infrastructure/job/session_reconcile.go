@@ -0,0 +1,58 @@
+package job
+
+import (
+	"auth-service/domain/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+)
+
+type SessionReconciler interface {
+	Reconcile(ctx context.Context) (int, error)
+}
+
+type SessionReconcileJob struct {
+	reconciler SessionReconciler
+	interval   time.Duration
+	log        *log.LogGRPCImpl
+}
+
+func NewSessionReconcileJob(
+	reconciler SessionReconciler,
+	interval time.Duration,
+	log *log.LogGRPCImpl,
+) *SessionReconcileJob {
+	return &SessionReconcileJob{
+		reconciler: reconciler,
+		interval:   interval,
+		log:        log,
+	}
+}
+
+func (j *SessionReconcileJob) Start(ctx context.Context, workers repository.WorkerGroup) {
+	workers.Go("session_reconcile", func(context.Context) error {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				j.Run(ctx)
+			}
+		}
+	})
+}
+
+func (j *SessionReconcileJob) Run(ctx context.Context) {
+	inserted, err := j.reconciler.Reconcile(ctx)
+	if err != nil {
+		j.log.Error("Failed to reconcile sessions: " + err.Error())
+		return
+	}
+	if inserted > 0 {
+		j.log.Info(fmt.Sprintf("Reconciled %d sessions missing in database", inserted))
+	}
+}
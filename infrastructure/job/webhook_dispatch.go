@@ -0,0 +1,52 @@
+package job
+
+import (
+	"auth-service/domain/repository"
+	"auth-service/domain/usecase"
+	"context"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+)
+
+const webhookDispatchInterval = 5 * time.Second
+
+type WebhookDispatchJob struct {
+	dispatchUc usecase.WebhookDispatchUsecase
+	log        *log.LogGRPCImpl
+}
+
+func NewWebhookDispatchJob(dispatchUc usecase.WebhookDispatchUsecase, log *log.LogGRPCImpl) *WebhookDispatchJob {
+	return &WebhookDispatchJob{
+		dispatchUc: dispatchUc,
+		log:        log,
+	}
+}
+
+func (j *WebhookDispatchJob) Start(ctx context.Context, workers repository.WorkerGroup) {
+	workers.Go("webhook_dispatch", func(context.Context) error {
+		ticker := time.NewTicker(webhookDispatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				j.Run(ctx)
+			}
+		}
+	})
+}
+
+func (j *WebhookDispatchJob) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivered, err := j.dispatchUc.Dispatch(ctx)
+		if err != nil {
+			j.log.Error("Failed to dispatch webhooks: " + err.Error())
+			return
+		}
+		if delivered == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"github.com/anhvanhoa/service-core/domain/cache"
+)
+
+type instrumentedCache struct {
+	cache.CacheI
+	lookup string
+}
+
+// NewCache bọc cache để đếm hit/miss của Get, lookup là tên luồng tra cứu (vd. "session")
+func NewCache(c cache.CacheI, lookup string) cache.CacheI {
+	return &instrumentedCache{CacheI: c, lookup: lookup}
+}
+
+func (c *instrumentedCache) Get(key string) ([]byte, error) {
+	v, err := c.CacheI.Get(key)
+	result := "hit"
+	if err != nil || v == nil {
+		result = "miss"
+	}
+	cacheLookups.WithLabelValues(c.lookup, result).Inc()
+	return v, err
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		rpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		rpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return res, err
+	}
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "auth"
+
+var (
+	rpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_requests_total",
+		Help:      "Số RPC đã xử lý theo method và mã gRPC.",
+	}, []string{"method", "code"})
+
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Thời gian xử lý RPC theo method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	authEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_total",
+		Help:      "Kết quả các luồng xác thực (login, refresh, register, verify, reset...) theo lý do.",
+	}, []string{"type", "outcome", "reason"})
+
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Số lần tra cứu Redis trước khi rơi xuống Postgres, theo hit/miss.",
+	}, []string{"lookup", "result"})
+
+	sagaStepFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "saga_step_failures_total",
+		Help:      "Số bước saga thất bại.",
+	}, []string{"step"})
+
+	sagaCompensations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "saga_compensations_total",
+		Help:      "Số lần chạy compensate của saga theo kết quả.",
+	}, []string{"step", "result"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Thời gian truy vấn Postgres theo loại câu lệnh.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "result"})
+
+	sessionWrites = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_writes_total",
+		Help:      "Kết quả ghi session bất đồng bộ xuống Postgres (written, retry, failed, discarded, sync).",
+	}, []string{"result"})
+
+	sessionWriteQueue = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "session_write_queue",
+		Help:      "Số session đang chờ ghi xuống Postgres.",
+	})
+
+	sessionReconciles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_reconcile_total",
+		Help:      "Kết quả đối soát session ghi lỗi giữa Redis và Postgres.",
+	}, []string{"result"})
+
+	registrationVerifyDelay = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "registration_verify_seconds",
+		Help:      "Thời gian từ lúc đăng ký tới lúc xác thực tài khoản.",
+		Buckets:   []float64{60, 300, 900, 3600, 6 * 3600, 24 * 3600, 3 * 24 * 3600, 7 * 24 * 3600, 30 * 24 * 3600},
+	})
+
+	unverifiedReminders = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "unverified_reminders_total",
+		Help:      "Số mail nhắc xác thực đã gửi cho tài khoản sắp bị xóa.",
+	})
+
+	unverifiedDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "unverified_deleted_total",
+		Help:      "Số tài khoản chưa xác thực bị xóa do quá hạn.",
+	})
+)
+
+func resultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// ObserveAuthEvent đếm kết quả một luồng xác thực, reason nên có ít giá trị (vd. mã gRPC)
+func ObserveAuthEvent(eventType, outcome, reason string) {
+	authEvents.WithLabelValues(eventType, outcome, reason).Inc()
+}
+
+func SagaStepFailed(step string) {
+	sagaStepFailures.WithLabelValues(step).Inc()
+}
+
+func SagaCompensated(step string, err error) {
+	sagaCompensations.WithLabelValues(step, resultLabel(err)).Inc()
+}
+
+func SessionWrite(result string) {
+	sessionWrites.WithLabelValues(result).Inc()
+}
+
+func SetSessionWriteQueue(n int) {
+	sessionWriteQueue.Set(float64(n))
+}
+
+func SessionReconciled(result string) {
+	sessionReconciles.WithLabelValues(result).Inc()
+}
+
+func ObserveRegistrationVerified(delay time.Duration) {
+	registrationVerifyDelay.Observe(delay.Seconds())
+}
+
+func UnverifiedReminded(n int) {
+	unverifiedReminders.Add(float64(n))
+}
+
+func UnverifiedDeleted(n int) {
+	unverifiedDeleted.Add(float64(n))
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type QueryHook struct{}
+
+var _ pg.QueryHook = QueryHook{}
+
+func (QueryHook) BeforeQuery(ctx context.Context, _ *pg.QueryEvent) (context.Context, error) {
+	return ctx, nil
+}
+
+func (QueryHook) AfterQuery(_ context.Context, e *pg.QueryEvent) error {
+	dbQueryDuration.WithLabelValues(operation(e), resultLabel(e.Err)).Observe(time.Since(e.StartTime).Seconds())
+	return nil
+}
+
+// operation lấy từ khóa đầu tiên của câu lệnh (SELECT, INSERT, ...), tránh nhãn có quá nhiều giá trị
+func operation(e *pg.QueryEvent) string {
+	q, err := e.UnformattedQuery()
+	if err != nil {
+		return "unknown"
+	}
+	q = bytes.TrimSpace(q)
+	if i := bytes.IndexAny(q, " \n\t("); i > 0 {
+		q = q[:i]
+	}
+	switch op := strings.ToUpper(string(q)); op {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH", "BEGIN", "COMMIT", "ROLLBACK":
+		return op
+	}
+	return "other"
+}
@@ -0,0 +1,255 @@
+package pwned
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	magic      = "PWNIDX01"
+	recordSize = sha1.Size
+)
+
+var (
+	ErrInvalidIndex = errors.New("pwned: file index không hợp lệ")
+	ErrInvalidLine  = errors.New("pwned: dòng dữ liệu không đúng định dạng HASH:COUNT")
+	ErrUnsorted     = errors.New("pwned: dữ liệu nguồn chưa được sắp xếp theo hash")
+)
+
+// Index tra cứu SHA-1 đã sắp xếp, mỗi bản ghi 20 byte. File được map vào bộ nhớ (mmap) thay vì đọc hết,
+// nên chỉ các trang mà tìm kiếm nhị phân đi qua được nạp và nhiều Index cùng file dùng chung page cache.
+type Index struct {
+	data  []byte
+	unmap func() error
+}
+
+// LoadIndex map file index vào bộ nhớ. File không được ghi đè tại chỗ khi đang được map,
+// cập nhật phải ghi ra file mới rồi rename như BuildIndex/MergeIndex.
+func LoadIndex(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size < int64(len(magic)) || (size-int64(len(magic)))%recordSize != 0 || int64(int(size)) != size {
+		return nil, ErrInvalidIndex
+	}
+	data, unmap, err := mapFile(f, int(size))
+	if err != nil {
+		return nil, err
+	}
+	if string(data[:len(magic)]) != magic {
+		unmap()
+		return nil, ErrInvalidIndex
+	}
+	return &Index{data: data[len(magic):], unmap: unmap}, nil
+}
+
+// Close bỏ map file, không được gọi Contains sau khi Close
+func (idx *Index) Close() error {
+	idx.data = nil
+	return idx.unmap()
+}
+
+func (idx *Index) Len() int {
+	return len(idx.data) / recordSize
+}
+
+func (idx *Index) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	return idx.ContainsHash(sum)
+}
+
+func (idx *Index) ContainsHash(sum [sha1.Size]byte) bool {
+	n := idx.Len()
+	i := sort.Search(n, func(i int) bool {
+		return bytes.Compare(idx.record(i), sum[:]) >= 0
+	})
+	return i < n && bytes.Equal(idx.record(i), sum[:])
+}
+
+func (idx *Index) record(i int) []byte {
+	return idx.data[i*recordSize : (i+1)*recordSize]
+}
+
+// BuildIndex đọc file HIBP (mỗi dòng "SHA1:COUNT", sắp xếp theo hash)
+// và ghi ra file index, bỏ qua các hash xuất hiện ít hơn minCount lần.
+func BuildIndex(src io.Reader, dstPath string, minCount int) (int, error) {
+	return writeIndex(dstPath, func(w *bufio.Writer) (int, error) {
+		return mergeRecords(w, nil, newSourceReader(src, minCount))
+	})
+}
+
+// MergeIndex gộp file HIBP mới vào index đã có, kết quả vẫn được sắp xếp
+// và không trùng lặp.
+func MergeIndex(src io.Reader, indexPath string, minCount int) (int, error) {
+	current, err := os.Open(indexPath)
+	if err != nil {
+		return 0, err
+	}
+	defer current.Close()
+
+	r := bufio.NewReader(current)
+	head := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, head); err != nil || string(head) != magic {
+		return 0, ErrInvalidIndex
+	}
+	return writeIndex(indexPath, func(w *bufio.Writer) (int, error) {
+		return mergeRecords(w, &indexReader{r: r}, newSourceReader(src, minCount))
+	})
+}
+
+func writeIndex(dstPath string, write func(w *bufio.Writer) (int, error)) (int, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(dstPath), filepath.Base(dstPath)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	count, err := func() (int, error) {
+		if _, err := w.WriteString(magic); err != nil {
+			return 0, err
+		}
+		count, err := write(w)
+		if err != nil {
+			return 0, err
+		}
+		return count, w.Flush()
+	}()
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return count, os.Rename(tmp.Name(), dstPath)
+}
+
+type recordReader interface {
+	next() ([]byte, error)
+}
+
+func mergeRecords(w *bufio.Writer, a, b recordReader) (int, error) {
+	var count int
+	var last []byte
+	read := func(r recordReader) ([]byte, error) {
+		if r == nil {
+			return nil, io.EOF
+		}
+		return r.next()
+	}
+	ra, errA := read(a)
+	rb, errB := read(b)
+	for errA == nil || errB == nil {
+		if errA != nil && errA != io.EOF {
+			return 0, errA
+		}
+		if errB != nil && errB != io.EOF {
+			return 0, errB
+		}
+		var rec []byte
+		switch {
+		case errB != nil || (errA == nil && bytes.Compare(ra, rb) <= 0):
+			rec = ra
+			ra, errA = read(a)
+		default:
+			rec = rb
+			rb, errB = read(b)
+		}
+		if last != nil {
+			cmp := bytes.Compare(rec, last)
+			if cmp < 0 {
+				return 0, ErrUnsorted
+			}
+			if cmp == 0 {
+				continue
+			}
+		}
+		if _, err := w.Write(rec); err != nil {
+			return 0, err
+		}
+		last = append(last[:0], rec...)
+		count++
+	}
+	if errA != io.EOF {
+		return 0, errA
+	}
+	if errB != io.EOF {
+		return 0, errB
+	}
+	return count, nil
+}
+
+type indexReader struct {
+	r *bufio.Reader
+}
+
+func (ir *indexReader) next() ([]byte, error) {
+	rec := make([]byte, recordSize)
+	if _, err := io.ReadFull(ir.r, rec); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, ErrInvalidIndex
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
+type sourceReader struct {
+	scanner  *bufio.Scanner
+	minCount int
+}
+
+func newSourceReader(src io.Reader, minCount int) *sourceReader {
+	return &sourceReader{
+		scanner:  bufio.NewScanner(src),
+		minCount: minCount,
+	}
+}
+
+func (sr *sourceReader) next() ([]byte, error) {
+	for sr.scanner.Scan() {
+		line := strings.TrimSpace(sr.scanner.Text())
+		if line == "" {
+			continue
+		}
+		hash, countStr, found := strings.Cut(line, ":")
+		if len(hash) != hex.EncodedLen(recordSize) {
+			return nil, ErrInvalidLine
+		}
+		rec, err := hex.DecodeString(hash)
+		if err != nil {
+			return nil, ErrInvalidLine
+		}
+		if found && sr.minCount > 1 {
+			count, err := strconv.Atoi(strings.TrimSpace(countStr))
+			if err != nil {
+				return nil, ErrInvalidLine
+			}
+			if count < sr.minCount {
+				continue
+			}
+		}
+		return rec, nil
+	}
+	if err := sr.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
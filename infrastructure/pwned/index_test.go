@@ -0,0 +1,214 @@
+package pwned
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func hashLine(password string, count int) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:])) + ":" + strconv.Itoa(count)
+}
+
+// source trả về dữ liệu HIBP đã sắp xếp theo hash như file gốc
+func source(lines ...string) *strings.Reader {
+	slices.Sort(lines)
+	return strings.NewReader(strings.Join(lines, "\n") + "\n")
+}
+
+func buildIndex(t *testing.T, minCount int, lines ...string) (string, int) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pwned.idx")
+	count, err := BuildIndex(source(lines...), path, minCount)
+	if err != nil {
+		t.Fatalf("BuildIndex: %v", err)
+	}
+	return path, count
+}
+
+func loadIndex(t *testing.T, path string) *Index {
+	t.Helper()
+	idx, err := LoadIndex(path)
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	t.Cleanup(func() { idx.Close() })
+	return idx
+}
+
+func TestBuildIndexLookup(t *testing.T) {
+	path, count := buildIndex(t, 1,
+		hashLine("password", 100),
+		hashLine("123456", 50),
+		hashLine("qwerty", 3),
+	)
+	if count != 3 {
+		t.Fatalf("count = %d, want 3", count)
+	}
+	idx := loadIndex(t, path)
+	if idx.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", idx.Len())
+	}
+	for _, p := range []string{"password", "123456", "qwerty"} {
+		if !idx.Contains(p) {
+			t.Errorf("Contains(%q) = false, want true", p)
+		}
+	}
+	for _, p := range []string{"", "Password", "correct horse battery staple"} {
+		if idx.Contains(p) {
+			t.Errorf("Contains(%q) = true, want false", p)
+		}
+	}
+}
+
+func TestBuildIndexMinCount(t *testing.T) {
+	path, count := buildIndex(t, 10,
+		hashLine("password", 100),
+		hashLine("rare", 9),
+		hashLine("edge", 10),
+	)
+	if count != 2 {
+		t.Fatalf("count = %d, want 2", count)
+	}
+	idx := loadIndex(t, path)
+	if !idx.Contains("password") || !idx.Contains("edge") {
+		t.Error("hashes at or above minCount must be kept")
+	}
+	if idx.Contains("rare") {
+		t.Error("hash below minCount must be skipped")
+	}
+}
+
+func TestBuildIndexRejectsBadSource(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want error
+	}{
+		{"short hash", "ABCDEF:1\n", ErrInvalidLine},
+		{"not hex", strings.Repeat("Z", 40) + ":1\n", ErrInvalidLine},
+		{"bad count", hashLine("a", 1)[:40] + ":x\n", ErrInvalidLine},
+		{"unsorted", strings.Repeat("F", 40) + ":5\n" + strings.Repeat("0", 40) + ":5\n", ErrUnsorted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pwned.idx")
+			if _, err := BuildIndex(strings.NewReader(tt.src), path, 2); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Fatal("failed build must not leave an index behind")
+			}
+		})
+	}
+}
+
+func TestBuildIndexDeduplicates(t *testing.T) {
+	line := hashLine("password", 5)
+	path := filepath.Join(t.TempDir(), "pwned.idx")
+	count, err := BuildIndex(strings.NewReader(line+"\n"+line+"\n\n"), path, 1)
+	if err != nil {
+		t.Fatalf("BuildIndex: %v", err)
+	}
+	if count != 1 || loadIndex(t, path).Len() != 1 {
+		t.Fatalf("count = %d, want 1", count)
+	}
+}
+
+func TestMergeIndex(t *testing.T) {
+	path, _ := buildIndex(t, 1,
+		hashLine("password", 100),
+		hashLine("qwerty", 3),
+	)
+	count, err := MergeIndex(source(
+		hashLine("qwerty", 4),
+		hashLine("letmein", 20),
+		hashLine("dragon", 1),
+	), path, 2)
+	if err != nil {
+		t.Fatalf("MergeIndex: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("count = %d, want 3", count)
+	}
+	idx := loadIndex(t, path)
+	for _, p := range []string{"password", "qwerty", "letmein"} {
+		if !idx.Contains(p) {
+			t.Errorf("Contains(%q) = false after merge", p)
+		}
+	}
+	if idx.Contains("dragon") {
+		t.Error("merge must apply minCount to the new source")
+	}
+	for i := 1; i < idx.Len(); i++ {
+		if string(idx.record(i-1)) >= string(idx.record(i)) {
+			t.Fatalf("records %d and %d are not strictly sorted", i-1, i)
+		}
+	}
+}
+
+func TestLoadedIndexSurvivesMerge(t *testing.T) {
+	path, _ := buildIndex(t, 1, hashLine("password", 100))
+	before := loadIndex(t, path)
+	if _, err := MergeIndex(source(hashLine("letmein", 20)), path, 1); err != nil {
+		t.Fatalf("MergeIndex: %v", err)
+	}
+	// MergeIndex thay file bằng rename nên index đang map vẫn đọc bản cũ
+	if !before.Contains("password") || before.Contains("letmein") {
+		t.Fatal("mapped index must keep reading the file it was loaded from")
+	}
+	after := loadIndex(t, path)
+	if !after.Contains("password") || !after.Contains("letmein") {
+		t.Fatal("index loaded after merge must see the merged records")
+	}
+}
+
+func TestMergeIndexRejectsInvalidIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pwned.idx")
+	if err := os.WriteFile(path, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MergeIndex(source(hashLine("a", 1)), path, 1); !errors.Is(err, ErrInvalidIndex) {
+		t.Fatalf("err = %v, want ErrInvalidIndex", err)
+	}
+}
+
+func TestLoadIndexRejectsInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string][]byte{
+		"empty":     nil,
+		"bad magic": []byte("NOTANIDX" + strings.Repeat("x", recordSize)),
+		"truncated": []byte(magic + strings.Repeat("x", recordSize-1)),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_"))
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadIndex(path); !errors.Is(err, ErrInvalidIndex) {
+				t.Fatalf("err = %v, want ErrInvalidIndex", err)
+			}
+		})
+	}
+	if _, err := LoadIndex(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("LoadIndex of a missing file must fail")
+	}
+}
+
+func TestEmptyIndex(t *testing.T) {
+	path, count := buildIndex(t, 1)
+	if count != 0 {
+		t.Fatalf("count = %d, want 0", count)
+	}
+	if loadIndex(t, path).Contains("password") {
+		t.Fatal("empty index must not contain anything")
+	}
+}
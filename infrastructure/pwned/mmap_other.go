@@ -0,0 +1,17 @@
+//go:build !unix
+
+package pwned
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile đọc cả file vào bộ nhớ trên hệ điều hành không hỗ trợ mmap qua syscall
+func mapFile(f *os.File, size int) ([]byte, func() error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
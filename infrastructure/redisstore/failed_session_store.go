@@ -0,0 +1,65 @@
+package redisstore
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const failedSessionTTL = 30 * 24 * time.Hour
+
+type failedSessionStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewFailedSessionStore lưu session ghi lỗi trong hash <key>, field là token:user_id, value là session dạng JSON.
+// Key được gia hạn mỗi lần ghi để session lỗi không bị giữ mãi khi job đối soát không chạy.
+func NewFailedSessionStore(client *redis.Client, key string) repository.FailedSessionStore {
+	return &failedSessionStore{
+		client: client,
+		key:    key,
+	}
+}
+
+func (s *failedSessionStore) Add(ctx context.Context, session entity.Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.key, failedSessionField(session), data)
+		pipe.Expire(ctx, s.key, failedSessionTTL)
+		return nil
+	})
+	return err
+}
+
+// List bỏ qua các field không đọc được thay vì làm hỏng cả lượt đối soát
+func (s *failedSessionStore) List(ctx context.Context) ([]entity.Session, error) {
+	values, err := s.client.HGetAll(ctx, s.key).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]entity.Session, 0, len(values))
+	for _, v := range values {
+		var session entity.Session
+		if err := json.Unmarshal([]byte(v), &session); err != nil {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (s *failedSessionStore) Remove(ctx context.Context, session entity.Session) error {
+	return s.client.HDel(ctx, s.key, failedSessionField(session)).Err()
+}
+
+func failedSessionField(session entity.Session) string {
+	return session.Token + ":" + session.UserID
+}
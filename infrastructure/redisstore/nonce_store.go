@@ -0,0 +1,26 @@
+package redisstore
+
+import (
+	"auth-service/domain/repository"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type nonceStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewNonceStore lưu nonce đã dùng dưới key <prefix><nonce> bằng SET NX
+func NewNonceStore(client *redis.Client, prefix string) repository.NonceStore {
+	return &nonceStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *nonceStore) Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.prefix+nonce, 1, ttl).Result()
+}
@@ -0,0 +1,65 @@
+package redisstore
+
+import (
+	"auth-service/domain/repository"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// addTokenScript thêm token, dọn token hết hạn và đặt hạn của key theo token hết hạn muộn nhất,
+// nên thêm lại token cũ không làm key hết hạn trước token mới hơn
+var addTokenScript = redis.NewScript(`
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[3])
+local last = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+if last[2] then
+	redis.call('EXPIREAT', KEYS[1], last[2])
+end
+return 1
+`)
+
+// takeTokensScript trả về token chưa hết hạn kèm score rồi xóa key
+var takeTokensScript = redis.NewScript(`
+local tokens = redis.call('ZRANGEBYSCORE', KEYS[1], '(' .. ARGV[1], '+inf', 'WITHSCORES')
+redis.call('DEL', KEYS[1])
+return tokens
+`)
+
+type tokenIndex struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewTokenIndex lưu token của user trong sorted set <prefix><user_id> với score là thời điểm hết hạn,
+// token hết hạn được dọn mỗi lần ghi
+func NewTokenIndex(client *redis.Client, prefix string) repository.TokenIndex {
+	return &tokenIndex{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (i *tokenIndex) Add(ctx context.Context, userID, token string, exp time.Time) error {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	return addTokenScript.Run(ctx, i.client, []string{i.prefix + userID}, exp.Unix(), token, now).Err()
+}
+
+func (i *tokenIndex) Take(ctx context.Context, userID string) (map[string]time.Time, error) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	values, err := takeTokensScript.Run(ctx, i.client, []string{i.prefix + userID}, now).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	tokens := make(map[string]time.Time, len(values)/2)
+	for n := 0; n+1 < len(values); n += 2 {
+		score, err := strconv.ParseFloat(values[n+1], 64)
+		if err != nil {
+			return nil, err
+		}
+		tokens[values[n]] = time.Unix(int64(score), 0)
+	}
+	return tokens, nil
+}
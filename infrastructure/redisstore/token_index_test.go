@@ -0,0 +1,63 @@
+package redisstore
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// testRedis trả về client tới Redis dùng riêng cho test, bỏ qua test khi chưa đặt AUTH_TEST_REDIS_URL
+func testRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	url := os.Getenv("AUTH_TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("AUTH_TEST_REDIS_URL is not set")
+	}
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("ParseURL: %v", err)
+	}
+	client := redis.NewClient(opt)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestTokenIndexTake(t *testing.T) {
+	client := testRedis(t)
+	ctx := context.Background()
+	prefix := "test_tokens:" + uuid.NewString() + ":"
+	index := NewTokenIndex(client, prefix)
+	t.Cleanup(func() { client.Del(ctx, prefix+"u1") })
+
+	later := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	sooner := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := index.Add(ctx, "u1", "new", later); err != nil {
+		t.Fatalf("Add(new): %v", err)
+	}
+	// thêm lại token cũ sau token mới không được rút ngắn hạn của chỉ mục
+	if err := index.Add(ctx, "u1", "old", sooner); err != nil {
+		t.Fatalf("Add(old): %v", err)
+	}
+	if ttl := client.TTL(ctx, prefix+"u1").Val(); ttl < time.Until(later)-time.Minute {
+		t.Fatalf("TTL = %s, want about %s", ttl, time.Until(later))
+	}
+
+	tokens, err := index.Take(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if len(tokens) != 2 || !tokens["new"].Equal(later) || !tokens["old"].Equal(sooner) {
+		t.Fatalf("Take = %v", tokens)
+	}
+	if n := client.Exists(ctx, prefix+"u1").Val(); n != 0 {
+		t.Fatal("Take must delete the index")
+	}
+	tokens, err = index.Take(ctx, "u1")
+	if err != nil || len(tokens) != 0 {
+		t.Fatalf("second Take = %v, %v; want empty", tokens, err)
+	}
+}
@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"auth-service/domain/repository"
+	"context"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type advisoryLocker struct {
+	db *pg.DB
+}
+
+func NewAdvisoryLocker(db *pg.DB) repository.AdvisoryLocker {
+	return &advisoryLocker{
+		db: db,
+	}
+}
+
+// TryWithLock giữ pg_try_advisory_lock trên một kết nối riêng trong lúc chạy fn,
+// khóa tự nhả nếu kết nối bị đóng khi replica chết giữa chừng
+func (l *advisoryLocker) TryWithLock(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	conn := l.db.Conn()
+	defer conn.Close()
+
+	var acquired bool
+	if _, err := conn.QueryOneContext(ctx, pg.Scan(&acquired), "SELECT pg_try_advisory_lock(hashtext(?))", name); err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(hashtext(?))", name)
+	return true, fn(ctx)
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type authEventRepository struct {
+	db pg.DBI
+}
+
+func NewAuthEventRepository(db *pg.DB) repository.AuthEventRepository {
+	return &authEventRepository{
+		db: db,
+	}
+}
+
+func (ar *authEventRepository) CreateAuthEvent(ctx context.Context, data entity.AuthEvent) error {
+	_, err := ar.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (ar *authEventRepository) ListAuthEvents(ctx context.Context, filter repository.AuthEventFilter) ([]entity.AuthEvent, int, error) {
+	var events []entity.AuthEvent
+	q := ar.db.ModelContext(ctx, &events)
+	if filter.UserID != "" {
+		q = q.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Type != "" {
+		q = q.Where("event_type = ?", filter.Type)
+	}
+	if filter.Outcome != "" {
+		q = q.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.IP != "" {
+		q = q.Where("ip = ?", filter.IP)
+	}
+	if filter.From != nil {
+		q = q.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("created_at < ?", *filter.To)
+	}
+	total, err := q.Order("created_at DESC", "id DESC").
+		Limit(filter.PageSize).
+		Offset((filter.Page - 1) * filter.PageSize).
+		SelectAndCount()
+	return events, total, err
+}
+
+func (ar *authEventRepository) DeleteAuthEventsBefore(ctx context.Context, before time.Time) (int, error) {
+	r, err := ar.db.ModelContext(ctx, &entity.AuthEvent{}).
+		Where("created_at < ?", before).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+	return r.RowsAffected(), nil
+}
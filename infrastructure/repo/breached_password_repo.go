@@ -0,0 +1,30 @@
+package repo
+
+import (
+	"auth-service/domain/repository"
+	"auth-service/infrastructure/pwned"
+)
+
+type breachedPasswordRepository struct {
+	index *pwned.Index
+}
+
+func NewBreachedPasswordRepository(indexPath string) (repository.BreachedPasswordRepository, error) {
+	if indexPath == "" {
+		return &breachedPasswordRepository{}, nil
+	}
+	index, err := pwned.LoadIndex(indexPath)
+	if err != nil {
+		return &breachedPasswordRepository{}, err
+	}
+	return &breachedPasswordRepository{
+		index: index,
+	}, nil
+}
+
+func (br *breachedPasswordRepository) IsBreached(password string) bool {
+	if br.index == nil {
+		return false
+	}
+	return br.index.Contains(password)
+}
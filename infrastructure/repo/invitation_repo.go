@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type invitationRepository struct {
+	db pg.DBI
+}
+
+func NewInvitationRepository(db *pg.DB) repository.InvitationRepository {
+	return &invitationRepository{
+		db: db,
+	}
+}
+
+func (ir *invitationRepository) CreateInvitation(ctx context.Context, data entity.Invitation) error {
+	_, err := ir.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (ir *invitationRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (entity.Invitation, error) {
+	var invitation entity.Invitation
+	err := ir.db.ModelContext(ctx, &invitation).Where("token_hash = ?", tokenHash).Select()
+	return invitation, err
+}
+
+func (ir *invitationRepository) RevokePendingByEmail(ctx context.Context, email string) error {
+	_, err := ir.db.ModelContext(ctx, &entity.Invitation{}).
+		Where("email = ?", email).
+		Where("accepted_at IS NULL").
+		Delete()
+	return err
+}
+
+func (ir *invitationRepository) MarkAccepted(ctx context.Context, id string) (bool, error) {
+	r, err := ir.db.ModelContext(ctx, &entity.Invitation{}).
+		Set("accepted_at = NOW()").
+		Where("id = ?", id).
+		Where("accepted_at IS NULL").
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return r.RowsAffected() > 0, nil
+}
+
+// DeleteExpiredInvitees chỉ xóa tài khoản do lời mời tạo sẵn: chưa xác thực, chưa có mật khẩu
+// và không còn lời mời nào đang hiệu lực. Tài khoản tự đăng ký có mật khẩu nên do luồng dọn tài khoản chưa xác thực xử lý.
+func (ir *invitationRepository) DeleteExpiredInvitees(ctx context.Context, limit int) (int, error) {
+	sub := ir.db.ModelContext(ctx, &entity.Invitation{}).
+		Column("user_id").
+		Where("accepted_at IS NULL").
+		Where("expires_at < NOW()").
+		Limit(limit)
+	r, err := ir.db.ModelContext(ctx, &entity.User{}).
+		Where("id IN (?)", sub).
+		Where("veryfied IS NULL").
+		Where("password IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM invitations AS pending WHERE pending.user_id = ?TableAlias.id AND pending.accepted_at IS NULL AND pending.expires_at >= NOW())").
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+	return r.RowsAffected(), nil
+}
+
+func (ir *invitationRepository) DeleteExpired(ctx context.Context, limit int) (int, error) {
+	sub := ir.db.ModelContext(ctx, &entity.Invitation{}).
+		Column("id").
+		Where("accepted_at IS NULL").
+		Where("expires_at < NOW()").
+		Limit(limit)
+	r, err := ir.db.ModelContext(ctx, &entity.Invitation{}).
+		Where("id IN (?)", sub).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+	return r.RowsAffected(), nil
+}
+
+func (ir *invitationRepository) Tx(ctx context.Context) repository.InvitationRepository {
+	tx := getTx(ctx, ir.db)
+	return &invitationRepository{
+		db: tx,
+	}
+}
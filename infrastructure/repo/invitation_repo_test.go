@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+)
+
+// testDB trả về transaction trên database đã chạy đủ migration (make up), rollback khi test xong
+func testDB(t *testing.T) *pg.Tx {
+	t.Helper()
+	url := os.Getenv("AUTH_TEST_DB_URL")
+	if url == "" {
+		t.Skip("AUTH_TEST_DB_URL is not set")
+	}
+	opt, err := pg.ParseURL(url)
+	if err != nil {
+		t.Fatalf("ParseURL: %v", err)
+	}
+	db := pg.Connect(opt)
+	t.Cleanup(func() { db.Close() })
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+func TestInviteeWithoutPassword(t *testing.T) {
+	tx := testDB(t)
+	ctx := context.Background()
+	users := &userRepository{db: tx}
+	invitations := &invitationRepository{db: tx}
+
+	inviter := entity.User{ID: uuid.NewString(), Email: uuid.NewString() + "@example.com", Password: "hash"}
+	invitee := entity.User{ID: uuid.NewString(), Email: uuid.NewString() + "@example.com", CreatedBy: inviter.ID}
+	for _, u := range []entity.User{inviter, invitee} {
+		if _, err := users.CreateUser(u); err != nil {
+			t.Fatalf("CreateUser(%s): %v", u.Email, err)
+		}
+	}
+	now := time.Now()
+	if err := invitations.CreateInvitation(ctx, entity.Invitation{
+		ID:        uuid.NewString(),
+		Email:     invitee.Email,
+		TokenHash: uuid.NewString(),
+		UserID:    invitee.ID,
+		InvitedBy: inviter.ID,
+		ExpiresAt: now.Add(-time.Hour),
+		CreatedAt: now.Add(-2 * time.Hour),
+	}); err != nil {
+		t.Fatalf("CreateInvitation: %v", err)
+	}
+
+	n, err := invitations.DeleteExpiredInvitees(ctx, 100)
+	if err != nil {
+		t.Fatalf("DeleteExpiredInvitees: %v", err)
+	}
+	if n < 1 {
+		t.Fatal("expired invitee was not deleted")
+	}
+	if _, err := users.GetUserByID(invitee.ID); err != pg.ErrNoRows {
+		t.Fatalf("GetUserByID(invitee) err = %v, want ErrNoRows", err)
+	}
+	// tài khoản có mật khẩu không bao giờ bị xóa theo lời mời
+	if _, err := users.GetUserByID(inviter.ID); err != nil {
+		t.Fatalf("GetUserByID(inviter): %v", err)
+	}
+}
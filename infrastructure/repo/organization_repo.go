@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type organizationRepository struct {
+	db pg.DBI
+}
+
+func NewOrganizationRepository(db *pg.DB) repository.OrganizationRepository {
+	return &organizationRepository{
+		db: db,
+	}
+}
+
+const (
+	organizationNameKeyIndex   = "idx_organizations_name_key"
+	organizationSlugConstraint = "organizations_slug_key"
+)
+
+func (or *organizationRepository) CreateOrganization(ctx context.Context, data entity.Organization) error {
+	_, err := or.db.ModelContext(ctx, &data).Insert()
+	var pgErr pg.Error
+	if errors.As(err, &pgErr) && pgErr.IntegrityViolation() {
+		switch pgErr.Field('n') {
+		case organizationNameKeyIndex:
+			return repository.ErrOrganizationNameTaken
+		case organizationSlugConstraint:
+			return repository.ErrOrganizationSlugTaken
+		}
+	}
+	return err
+}
+
+func (or *organizationRepository) GetOrganizationByID(ctx context.Context, id string) (entity.Organization, error) {
+	var org entity.Organization
+	err := or.db.ModelContext(ctx, &org).Where("id = ?", id).Select()
+	return org, err
+}
+
+func (or *organizationRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	return or.db.ModelContext(ctx, &entity.Organization{}).Where("slug = ?", slug).Exists()
+}
+
+func (or *organizationRepository) NameExists(ctx context.Context, name, createdBy string) (bool, error) {
+	q := or.db.ModelContext(ctx, &entity.Organization{}).Where("LOWER(name) = LOWER(?)", name)
+	if createdBy != "" {
+		q = q.Where("created_by = ?", createdBy)
+	}
+	return q.Exists()
+}
+
+func (or *organizationRepository) AddMember(ctx context.Context, data entity.OrganizationMember) error {
+	_, err := or.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (or *organizationRepository) GetMember(ctx context.Context, organizationID, userID string) (entity.OrganizationMember, error) {
+	var member entity.OrganizationMember
+	err := or.db.ModelContext(ctx, &member).
+		Where("organization_id = ?", organizationID).
+		Where("user_id = ?", userID).
+		Select()
+	return member, err
+}
+
+func (or *organizationRepository) ListMembershipsByUser(ctx context.Context, userID string) ([]entity.OrganizationMember, error) {
+	var members []entity.OrganizationMember
+	err := or.db.ModelContext(ctx, &members).
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Select()
+	return members, err
+}
+
+func (or *organizationRepository) Tx(ctx context.Context) repository.OrganizationRepository {
+	tx := getTx(ctx, or.db)
+	return &organizationRepository{
+		db: tx,
+	}
+}
@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateOrganizationUniqueKeys(t *testing.T) {
+	tx := testDB(t)
+	ctx := context.Background()
+	orgs := &organizationRepository{db: tx}
+	suffix := uuid.NewString()[:8]
+
+	org := entity.Organization{ID: uuid.NewString(), Name: "Acme", NameKey: "acme-" + suffix, Slug: "acme-" + suffix}
+	if err := orgs.CreateOrganization(ctx, org); err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	for _, tt := range []struct {
+		name string
+		org  entity.Organization
+		want error
+	}{
+		{name: "same name key", org: entity.Organization{ID: uuid.NewString(), Name: "ACME", NameKey: org.NameKey, Slug: "other-" + suffix}, want: repository.ErrOrganizationNameTaken},
+		{name: "same slug", org: entity.Organization{ID: uuid.NewString(), Name: "Other", NameKey: "other-" + suffix, Slug: org.Slug}, want: repository.ErrOrganizationSlugTaken},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// savepoint để lỗi ràng buộc không làm hỏng transaction của test
+			if _, err := tx.Exec("SAVEPOINT unique_keys"); err != nil {
+				t.Fatalf("SAVEPOINT: %v", err)
+			}
+			defer tx.Exec("ROLLBACK TO SAVEPOINT unique_keys")
+			if err := orgs.CreateOrganization(ctx, tt.org); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type outboxRepository struct {
+	db pg.DBI
+}
+
+func NewOutboxRepository(db *pg.DB) repository.OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (or *outboxRepository) CreateOutboxMessage(ctx context.Context, data entity.OutboxMessage) error {
+	_, err := or.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (or *outboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	var messages []entity.OutboxMessage
+	due := or.db.ModelContext(ctx, &entity.OutboxMessage{}).
+		Column("id").
+		Where("status = ?", entity.OutboxPending).
+		Where("next_attempt_at <= NOW()").
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+	_, err := or.db.ModelContext(ctx, &messages).
+		Set("next_attempt_at = ?", time.Now().Add(lease)).
+		Where("id IN (?)", due).
+		Returning("*").
+		Update()
+	return messages, err
+}
+
+func (or *outboxRepository) SetTaskID(ctx context.Context, id, taskID string) error {
+	_, err := or.db.ModelContext(ctx, &entity.OutboxMessage{}).
+		Set("task_id = ?", taskID).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (or *outboxRepository) MarkDelivered(ctx context.Context, id string) error {
+	_, err := or.db.ModelContext(ctx, &entity.OutboxMessage{}).
+		Set("status = ?", entity.OutboxDelivered).
+		Set("delivered_at = NOW()").
+		Set("last_error = NULL").
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (or *outboxRepository) MarkRetry(ctx context.Context, id string, attempts int, next time.Time, lastError string) error {
+	_, err := or.db.ModelContext(ctx, &entity.OutboxMessage{}).
+		Set("attempts = ?", attempts).
+		Set("next_attempt_at = ?", next).
+		Set("last_error = ?", lastError).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (or *outboxRepository) MarkFailed(ctx context.Context, id string, attempts int, lastError string) error {
+	_, err := or.db.ModelContext(ctx, &entity.OutboxMessage{}).
+		Set("status = ?", entity.OutboxFailed).
+		Set("attempts = ?", attempts).
+		Set("last_error = ?", lastError).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (or *outboxRepository) DeleteDeliveredBefore(ctx context.Context, before time.Time) (int, error) {
+	r, err := or.db.ModelContext(ctx, &entity.OutboxMessage{}).
+		Where("status = ?", entity.OutboxDelivered).
+		Where("delivered_at < ?", before).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+	return r.RowsAffected(), nil
+}
+
+func (or *outboxRepository) Tx(ctx context.Context) repository.OutboxRepository {
+	tx := getTx(ctx, or.db)
+	return &outboxRepository{
+		db: tx,
+	}
+}
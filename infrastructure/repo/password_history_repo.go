@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type passwordHistoryRepository struct {
+	db pg.DBI
+}
+
+func NewPasswordHistoryRepository(db *pg.DB) repository.PasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		db: db,
+	}
+}
+
+func (pr *passwordHistoryRepository) CreatePasswordHistory(ctx context.Context, data entity.PasswordHistory) error {
+	_, err := pr.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (pr *passwordHistoryRepository) GetRecentByUserID(userID string, limit int) ([]entity.PasswordHistory, error) {
+	var histories []entity.PasswordHistory
+	err := pr.db.Model(&histories).
+		Where("user_id = ?", userID).
+		Order("created_at DESC", "id DESC").
+		Limit(limit).
+		Select()
+	return histories, err
+}
+
+func (pr *passwordHistoryRepository) PruneByUserID(ctx context.Context, userID string, keep int) error {
+	recent := pr.db.ModelContext(ctx, &entity.PasswordHistory{}).
+		Column("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC", "id DESC").
+		Limit(keep)
+	_, err := pr.db.ModelContext(ctx, &entity.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Where("id NOT IN (?)", recent).
+		Delete()
+	return err
+}
+
+func (pr *passwordHistoryRepository) Tx(ctx context.Context) repository.PasswordHistoryRepository {
+	tx := getTx(ctx, pr.db)
+	return &passwordHistoryRepository{
+		db: tx,
+	}
+}
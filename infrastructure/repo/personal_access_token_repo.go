@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type personalAccessTokenRepository struct {
+	db pg.DBI
+}
+
+func NewPersonalAccessTokenRepository(db *pg.DB) repository.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		db: db,
+	}
+}
+
+func (pr *personalAccessTokenRepository) CreateToken(ctx context.Context, data entity.PersonalAccessToken) error {
+	_, err := pr.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (pr *personalAccessTokenRepository) GetTokenByHash(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	var token entity.PersonalAccessToken
+	err := pr.db.ModelContext(ctx, &token).Where("token_hash = ?", tokenHash).Select()
+	return token, err
+}
+
+func (pr *personalAccessTokenRepository) ListTokensByUser(ctx context.Context, userID string) ([]entity.PersonalAccessToken, error) {
+	var tokens []entity.PersonalAccessToken
+	err := pr.db.ModelContext(ctx, &tokens).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Select()
+	return tokens, err
+}
+
+func (pr *personalAccessTokenRepository) NameExists(ctx context.Context, userID, name string) (bool, error) {
+	return pr.db.ModelContext(ctx, &entity.PersonalAccessToken{}).
+		Where("user_id = ?", userID).
+		Where("name = ?", name).
+		Where("revoked_at IS NULL").
+		Exists()
+}
+
+func (pr *personalAccessTokenRepository) RevokeToken(ctx context.Context, userID, id string) (entity.PersonalAccessToken, bool, error) {
+	var tokens []entity.PersonalAccessToken
+	_, err := pr.db.ModelContext(ctx, &tokens).
+		Set("revoked_at = NOW()").
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Returning("*").
+		Update()
+	if err != nil || len(tokens) == 0 {
+		return entity.PersonalAccessToken{}, false, err
+	}
+	return tokens[0], true, nil
+}
+
+func (pr *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, id string) error {
+	_, err := pr.db.ModelContext(ctx, &entity.PersonalAccessToken{}).
+		Set("last_used_at = NOW()").
+		Where("id = ?", id).
+		Update()
+	return err
+}
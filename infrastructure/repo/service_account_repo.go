@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type serviceAccountRepository struct {
+	db pg.DBI
+}
+
+func NewServiceAccountRepository(db *pg.DB) repository.ServiceAccountRepository {
+	return &serviceAccountRepository{
+		db: db,
+	}
+}
+
+func (sr *serviceAccountRepository) CreateServiceAccount(ctx context.Context, data entity.ServiceAccount) error {
+	_, err := sr.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (sr *serviceAccountRepository) GetServiceAccountByID(ctx context.Context, id string) (entity.ServiceAccount, error) {
+	var account entity.ServiceAccount
+	err := sr.db.ModelContext(ctx, &account).Where("id = ?", id).Select()
+	return account, err
+}
+
+func (sr *serviceAccountRepository) ListServiceAccounts(ctx context.Context, filter repository.ServiceAccountFilter) ([]entity.ServiceAccount, error) {
+	var accounts []entity.ServiceAccount
+	q := sr.db.ModelContext(ctx, &accounts)
+	if filter.OwnerUserID != "" {
+		q = q.Where("owner_user_id = ?", filter.OwnerUserID)
+	}
+	if filter.OwnerOrganizationID != "" {
+		q = q.Where("owner_organization_id = ?", filter.OwnerOrganizationID)
+	}
+	err := q.Order("created_at DESC").Select()
+	return accounts, err
+}
+
+func (sr *serviceAccountRepository) SetDisabled(ctx context.Context, id string, disabled bool) error {
+	q := sr.db.ModelContext(ctx, &entity.ServiceAccount{}).Where("id = ?", id)
+	if disabled {
+		q = q.Set("disabled_at = COALESCE(disabled_at, NOW())")
+	} else {
+		q = q.Set("disabled_at = NULL")
+	}
+	_, err := q.Update()
+	return err
+}
+
+func (sr *serviceAccountRepository) CreateKey(ctx context.Context, data entity.ServiceAccountKey) error {
+	_, err := sr.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (sr *serviceAccountRepository) GetKeyByID(ctx context.Context, id string) (entity.ServiceAccountKey, error) {
+	var key entity.ServiceAccountKey
+	err := sr.db.ModelContext(ctx, &key).Where("id = ?", id).Select()
+	return key, err
+}
+
+func (sr *serviceAccountRepository) ListKeys(ctx context.Context, serviceAccountID string) ([]entity.ServiceAccountKey, error) {
+	var keys []entity.ServiceAccountKey
+	err := sr.db.ModelContext(ctx, &keys).
+		Where("service_account_id = ?", serviceAccountID).
+		Order("created_at DESC").
+		Select()
+	return keys, err
+}
+
+func (sr *serviceAccountRepository) RevokeKey(ctx context.Context, serviceAccountID, keyID string) (bool, error) {
+	r, err := sr.db.ModelContext(ctx, &entity.ServiceAccountKey{}).
+		Set("revoked_at = NOW()").
+		Where("id = ?", keyID).
+		Where("service_account_id = ?", serviceAccountID).
+		Where("revoked_at IS NULL").
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return r.RowsAffected() > 0, nil
+}
+
+func (sr *serviceAccountRepository) TouchKeyLastUsed(ctx context.Context, id string) error {
+	_, err := sr.db.ModelContext(ctx, &entity.ServiceAccountKey{}).
+		Set("last_used_at = NOW()").
+		Where("id = ?", id).
+		Update()
+	return err
+}
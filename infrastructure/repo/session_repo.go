@@ -26,6 +26,11 @@ func (sr *sessionRepositoryImpl) CreateSession(data entity.Session) error {
 	return nil
 }
 
+// CreateSessionAsync ghi đồng bộ, chế độ ghi sau do WriteBehindSessionRepository đảm nhận
+func (sr *sessionRepositoryImpl) CreateSessionAsync(data entity.Session) error {
+	return sr.CreateSession(data)
+}
+
 func (sr *sessionRepositoryImpl) GetSessionAliveByToken(typeSession entity.SessionType, token string) (entity.Session, error) {
 	var session entity.Session
 	err := sr.db.Model(&session).Where("token = ?", token).Where("type = ?", typeSession).
@@ -55,6 +60,16 @@ func (sr *sessionRepositoryImpl) GetSessionForgotAliveByTokenAndIdUser(token, id
 	return sr.GetSessionAliveByTokenAndIdUser(entity.SessionTypeForgot, token, idUser)
 }
 
+func (sr *sessionRepositoryImpl) GetSessionsByUserID(sessionType entity.SessionType, userID string) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := sr.db.Model(&sessions).
+		Where("type = ?", sessionType).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Select()
+	return sessions, err
+}
+
 func (sr *sessionRepositoryImpl) TokenExists(token string) bool {
 	count, err := sr.db.Model(&entity.Session{}).Where("token = ?", token).
 		Where("expired_at > NOW()").
@@ -101,14 +116,15 @@ func (sr *sessionRepositoryImpl) DeleteSessionForgotByToken(ctx context.Context,
 	return sr.DeleteSessionByTypeAndToken(ctx, entity.SessionTypeForgot, token)
 }
 
-func (sr *sessionRepositoryImpl) DeleteAllSessionsExpired(ctx context.Context) error {
-	_, err := sr.db.ModelContext(ctx, &entity.Session{}).
-		Where("expired_at < NOW()").
+// DeleteExpiredSessions xóa tối đa limit session hết hạn mỗi lần để không khóa bảng lâu
+func (sr *sessionRepositoryImpl) DeleteExpiredSessions(ctx context.Context, limit int) (int, error) {
+	r, err := sr.db.ModelContext(ctx, &entity.Session{}).
+		Where("ctid IN (SELECT ctid FROM sessions WHERE expired_at < NOW() LIMIT ?)", limit).
 		Delete()
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	return r.RowsAffected(), nil
 }
 
 func (sr *sessionRepositoryImpl) DeleteSessionForgotByTokenAndIdUser(ctx context.Context, token, idUser string) error {
@@ -0,0 +1,344 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"auth-service/infrastructure/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anhvanhoa/service-core/domain/cache"
+	"github.com/anhvanhoa/service-core/domain/log"
+	"github.com/go-pg/pg/v10"
+)
+
+const (
+	sessionRetryBaseDelay = 200 * time.Millisecond
+	sessionRetryMaxDelay  = 10 * time.Second
+)
+
+const (
+	defaultSessionQueueSize  = 1024
+	defaultSessionWorkers    = 4
+	defaultSessionMaxRetries = 5
+)
+
+type WriteBehindConfig struct {
+	QueueSize  int
+	Workers    int
+	MaxRetries int
+}
+
+type pendingSession struct {
+	mu        sync.Mutex
+	session   entity.Session
+	discarded bool
+}
+
+type sessionWriter struct {
+	inner   repository.SessionRepository
+	cache   cache.CacheI
+	failed  repository.FailedSessionStore
+	log     *log.LogGRPCImpl
+	retries int
+	queue   chan *pendingSession
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	closed  bool
+	pending map[*pendingSession]struct{}
+	wg      sync.WaitGroup
+}
+
+// WriteBehindSessionRepository ghi session xuống Postgres qua hàng đợi có giới hạn sau khi Redis đã nhận,
+// lỗi được thử lại với backoff và session ghi lỗi được lưu lại để job đối soát xử lý.
+// Các hàm còn lại đi thẳng xuống repository bên dưới.
+type WriteBehindSessionRepository struct {
+	repository.SessionRepository
+	writer *sessionWriter
+	inTx   bool
+}
+
+func NewWriteBehindSessionRepository(
+	inner repository.SessionRepository,
+	cache cache.CacheI,
+	failed repository.FailedSessionStore,
+	config WriteBehindConfig,
+	log *log.LogGRPCImpl,
+) *WriteBehindSessionRepository {
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultSessionQueueSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaultSessionWorkers
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaultSessionMaxRetries
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &sessionWriter{
+		inner:   inner,
+		cache:   cache,
+		failed:  failed,
+		log:     log,
+		retries: config.MaxRetries,
+		queue:   make(chan *pendingSession, config.QueueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: make(map[*pendingSession]struct{}),
+	}
+	for range config.Workers {
+		w.wg.Add(1)
+		go w.run()
+	}
+	return &WriteBehindSessionRepository{
+		SessionRepository: inner,
+		writer:            w,
+	}
+}
+
+// CreateSessionAsync đưa session vào hàng đợi, hàng đợi đầy hoặc đang tắt service thì ghi đồng bộ
+func (r *WriteBehindSessionRepository) CreateSessionAsync(data entity.Session) error {
+	if r.inTx {
+		return r.SessionRepository.CreateSession(data)
+	}
+	w := r.writer
+	p := &pendingSession{session: data}
+	w.mu.Lock()
+	if !w.closed {
+		w.pending[p] = struct{}{}
+		select {
+		case w.queue <- p:
+			w.mu.Unlock()
+			metrics.SetSessionWriteQueue(len(w.queue))
+			return nil
+		default:
+			delete(w.pending, p)
+		}
+	}
+	w.mu.Unlock()
+	metrics.SessionWrite("sync")
+	return r.SessionRepository.CreateSession(data)
+}
+
+func (r *WriteBehindSessionRepository) DeleteSessionByTypeAndUserID(ctx context.Context, sessionType entity.SessionType, userID string) error {
+	r.writer.discard(func(s entity.Session) bool {
+		return s.Type == sessionType && s.UserID == userID
+	})
+	return r.SessionRepository.DeleteSessionByTypeAndUserID(ctx, sessionType, userID)
+}
+
+func (r *WriteBehindSessionRepository) DeleteSessionByTypeAndToken(ctx context.Context, sessionType entity.SessionType, token string) error {
+	r.writer.discard(func(s entity.Session) bool {
+		return s.Type == sessionType && s.Token == token
+	})
+	return r.SessionRepository.DeleteSessionByTypeAndToken(ctx, sessionType, token)
+}
+
+func (r *WriteBehindSessionRepository) DeleteSessionAuthByToken(ctx context.Context, token string) error {
+	return r.DeleteSessionByTypeAndToken(ctx, entity.SessionTypeAuth, token)
+}
+
+func (r *WriteBehindSessionRepository) DeleteSessionForgotByToken(ctx context.Context, token string) error {
+	return r.DeleteSessionByTypeAndToken(ctx, entity.SessionTypeForgot, token)
+}
+
+func (r *WriteBehindSessionRepository) DeleteSessionForgotByTokenAndIdUser(ctx context.Context, token, idUser string) error {
+	r.writer.discard(func(s entity.Session) bool {
+		return s.Type == entity.SessionTypeForgot && s.Token == token && s.UserID == idUser
+	})
+	return r.SessionRepository.DeleteSessionForgotByTokenAndIdUser(ctx, token, idUser)
+}
+
+func (r *WriteBehindSessionRepository) DeleteAllSessionsForgot(ctx context.Context) error {
+	r.writer.discard(func(s entity.Session) bool {
+		return s.Type == entity.SessionTypeForgot
+	})
+	return r.SessionRepository.DeleteAllSessionsForgot(ctx)
+}
+
+// Tx trong transaction ghi đồng bộ để session commit/rollback cùng transaction
+func (r *WriteBehindSessionRepository) Tx(ctx context.Context) repository.SessionRepository {
+	return &WriteBehindSessionRepository{
+		SessionRepository: r.SessionRepository.Tx(ctx),
+		writer:            r.writer,
+		inTx:              true,
+	}
+}
+
+// Close ngừng nhận session mới vào hàng đợi và chờ ghi hết. Hết ctx thì worker ngừng thử lại, các session còn trong
+// hàng đợi được lưu để đối soát và Close chờ các lần ghi đang chạy kết thúc, nên sau khi trả về không còn ghi xuống DB.
+func (r *WriteBehindSessionRepository) Close(ctx context.Context) error {
+	w := r.writer
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		for p := range w.queue {
+			w.park(p)
+		}
+		<-done
+		return ctx.Err()
+	}
+}
+
+// discard đánh dấu các session đang chờ ghi đã bị xóa để worker không ghi lại sau lệnh xóa.
+// Session đang được ghi sẽ chờ ghi xong rồi mới trả về, lệnh xóa phía sau vẫn thấy bản ghi.
+func (w *sessionWriter) discard(match func(s entity.Session) bool) {
+	w.mu.Lock()
+	var matched []*pendingSession
+	for p := range w.pending {
+		if match(p.session) {
+			matched = append(matched, p)
+		}
+	}
+	w.mu.Unlock()
+	for _, p := range matched {
+		p.mu.Lock()
+		p.discarded = true
+		p.mu.Unlock()
+	}
+}
+
+func (w *sessionWriter) run() {
+	defer w.wg.Done()
+	for p := range w.queue {
+		metrics.SetSessionWriteQueue(len(w.queue))
+		if w.ctx.Err() != nil {
+			w.park(p)
+			continue
+		}
+		w.write(p)
+		w.mu.Lock()
+		delete(w.pending, p)
+		w.mu.Unlock()
+	}
+}
+
+// park lưu session chưa ghi để đối soát khi service tắt quá hạn, session đã bị xóa thì bỏ qua
+func (w *sessionWriter) park(p *pendingSession) {
+	w.mu.Lock()
+	delete(w.pending, p)
+	w.mu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discarded {
+		metrics.SessionWrite("discarded")
+		return
+	}
+	metrics.SessionWrite("failed")
+	if err := w.failed.Add(context.Background(), p.session); err != nil {
+		w.log.Error("Failed to save session for reconciliation: " + err.Error())
+	}
+}
+
+func (w *sessionWriter) write(p *pendingSession) {
+	delay := sessionRetryBaseDelay
+	var err error
+	for attempt := 0; ; attempt++ {
+		var discarded bool
+		p.mu.Lock()
+		discarded = p.discarded
+		if !discarded {
+			err = w.inner.CreateSession(p.session)
+		}
+		p.mu.Unlock()
+		if discarded {
+			metrics.SessionWrite("discarded")
+			return
+		}
+		if err == nil || isDuplicateKey(err) {
+			metrics.SessionWrite("written")
+			return
+		}
+		if attempt >= w.retries || w.ctx.Err() != nil {
+			break
+		}
+		metrics.SessionWrite("retry")
+		select {
+		case <-w.ctx.Done():
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, sessionRetryMaxDelay)
+	}
+	metrics.SessionWrite("failed")
+	w.log.Error(fmt.Sprintf("Failed to persist session of user %s: %s", p.session.UserID, err.Error()))
+	// w.ctx có thể đã bị hủy khi tắt service, session vẫn phải được lưu để đối soát
+	if err := w.failed.Add(context.Background(), p.session); err != nil {
+		w.log.Error("Failed to save session for reconciliation: " + err.Error())
+	}
+}
+
+func isDuplicateKey(err error) bool {
+	var pgErr pg.Error
+	return errors.As(err, &pgErr) && pgErr.IntegrityViolation()
+}
+
+// Reconcile đối soát các session ghi lỗi: session còn key trong Redis mà chưa có dòng trong sessions thì ghi lại,
+// session đã hết hạn hoặc không còn trong Redis (đã đăng xuất/đã dùng) thì bỏ qua.
+// Trả về số session đã ghi lại.
+func (r *WriteBehindSessionRepository) Reconcile(ctx context.Context) (int, error) {
+	w := r.writer
+	failed, err := w.failed.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	inserted := 0
+	now := time.Now()
+	var errs []error
+	for _, s := range failed {
+		if ctx.Err() != nil {
+			break
+		}
+		switch {
+		case s.ExpiredAt.Before(now), !w.cached(s), w.persisted(s):
+			metrics.SessionReconciled("dropped")
+		default:
+			if err := w.inner.CreateSession(s); err != nil && !isDuplicateKey(err) {
+				metrics.SessionReconciled("error")
+				continue
+			}
+			metrics.SessionReconciled("inserted")
+			inserted++
+		}
+		errs = append(errs, w.failed.Remove(ctx, s))
+	}
+	return inserted, errors.Join(errs...)
+}
+
+func (w *sessionWriter) persisted(s entity.Session) bool {
+	_, err := w.inner.GetSessionAliveByTokenAndIdUser(s.Type, s.Token, s.UserID)
+	return err == nil
+}
+
+// cached kiểm tra session còn trong Redis, mã quên mật khẩu dạng code được lưu theo key code:user_id
+func (w *sessionWriter) cached(s entity.Session) bool {
+	keys := []string{s.Token}
+	if s.Type == entity.SessionTypeForgot {
+		keys = append(keys, s.Token+":"+s.UserID)
+	}
+	for _, key := range keys {
+		if v, err := w.cache.Get(key); err == nil && v != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingSessionRepo giữ lần ghi session đầu tiên cho tới khi test đóng release và ghi lại thứ tự ghi/xóa
+type blockingSessionRepo struct {
+	repository.SessionRepository
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	events  []string
+}
+
+func newBlockingSessionRepo() *blockingSessionRepo {
+	return &blockingSessionRepo{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+}
+
+func (r *blockingSessionRepo) CreateSession(data entity.Session) error {
+	r.once.Do(func() { close(r.started) })
+	<-r.release
+	r.record("create:" + data.Token)
+	return nil
+}
+
+func (r *blockingSessionRepo) DeleteSessionByTypeAndToken(_ context.Context, _ entity.SessionType, token string) error {
+	r.record("delete:" + token)
+	return nil
+}
+
+func (r *blockingSessionRepo) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *blockingSessionRepo) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+type memoryFailedSessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]entity.Session
+}
+
+func (s *memoryFailedSessionStore) Add(_ context.Context, session entity.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = make(map[string]entity.Session)
+	}
+	s.sessions[session.Token] = session
+	return nil
+}
+
+func (s *memoryFailedSessionStore) List(context.Context) ([]entity.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]entity.Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		list = append(list, session)
+	}
+	return list, nil
+}
+
+func (s *memoryFailedSessionStore) Remove(_ context.Context, session entity.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, session.Token)
+	return nil
+}
+
+func (s *memoryFailedSessionStore) tokens() []string {
+	list, _ := s.List(context.Background())
+	tokens := make([]string, len(list))
+	for i, session := range list {
+		tokens[i] = session.Token
+	}
+	slices.Sort(tokens)
+	return tokens
+}
+
+func newTestSession(token string) entity.Session {
+	return entity.Session{
+		Token:     token,
+		UserID:    "user-1",
+		Type:      entity.SessionTypeAuth,
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestWriteBehindDeleteOrdering(t *testing.T) {
+	inner := newBlockingSessionRepo()
+	failed := &memoryFailedSessionStore{}
+	r := NewWriteBehindSessionRepository(inner, nil, failed, WriteBehindConfig{Workers: 1, QueueSize: 4}, nil)
+	ctx := context.Background()
+
+	if err := r.CreateSessionAsync(newTestSession("a")); err != nil {
+		t.Fatalf("CreateSessionAsync(a): %v", err)
+	}
+	<-inner.started
+	// worker duy nhất đang ghi a nên b nằm lại trong hàng đợi
+	if err := r.CreateSessionAsync(newTestSession("b")); err != nil {
+		t.Fatalf("CreateSessionAsync(b): %v", err)
+	}
+
+	deleted := make(chan error, 1)
+	go func() {
+		deleted <- r.DeleteSessionByTypeAndToken(ctx, entity.SessionTypeAuth, "a")
+	}()
+	select {
+	case err := <-deleted:
+		t.Fatalf("delete of a session being written returned before the write finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := r.DeleteSessionByTypeAndToken(ctx, entity.SessionTypeAuth, "b"); err != nil {
+		t.Fatalf("DeleteSessionByTypeAndToken(b): %v", err)
+	}
+
+	close(inner.release)
+	if err := <-deleted; err != nil {
+		t.Fatalf("DeleteSessionByTypeAndToken(a): %v", err)
+	}
+	if err := r.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// a được ghi trước lệnh xóa của nó, b bị xóa khi còn trong hàng đợi nên không bao giờ được ghi
+	want := []string{"delete:b", "create:a", "delete:a"}
+	if got := inner.recorded(); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if got := failed.tokens(); len(got) != 0 {
+		t.Fatalf("failed sessions = %v, want none", got)
+	}
+}
+
+func TestWriteBehindCloseTimeout(t *testing.T) {
+	inner := newBlockingSessionRepo()
+	failed := &memoryFailedSessionStore{}
+	r := NewWriteBehindSessionRepository(inner, nil, failed, WriteBehindConfig{Workers: 1, QueueSize: 4}, nil)
+
+	for _, token := range []string{"a", "b", "c"} {
+		if err := r.CreateSessionAsync(newTestSession(token)); err != nil {
+			t.Fatalf("CreateSessionAsync(%s): %v", token, err)
+		}
+		if token == "a" {
+			<-inner.started
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	closed := make(chan error, 1)
+	go func() { closed <- r.Close(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for !slices.Equal(failed.tokens(), []string{"b", "c"}) {
+		if time.Now().After(deadline) {
+			t.Fatalf("queued sessions not parked after timeout, failed = %v", failed.tokens())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case err := <-closed:
+		t.Fatalf("Close returned while a write was still running: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(inner.release)
+	if err := <-closed; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := inner.recorded(); !slices.Equal(got, []string{"create:a"}) {
+		t.Fatalf("events = %v, want only the in-flight write", got)
+	}
+	if got := failed.tokens(); !slices.Equal(got, []string{"b", "c"}) {
+		t.Fatalf("failed sessions = %v, want [b c]", got)
+	}
+}
@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"auth-service/domain/entity"
+	"auth-service/domain/repository"
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type webhookRepository struct {
+	db pg.DBI
+}
+
+func NewWebhookRepository(db *pg.DB) repository.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (wr *webhookRepository) CreateSubscription(ctx context.Context, data entity.WebhookSubscription) error {
+	_, err := wr.db.ModelContext(ctx, &data).Insert()
+	return err
+}
+
+func (wr *webhookRepository) GetSubscriptionByID(ctx context.Context, id string) (entity.WebhookSubscription, error) {
+	var sub entity.WebhookSubscription
+	err := wr.db.ModelContext(ctx, &sub).Where("id = ?", id).Select()
+	return sub, err
+}
+
+func (wr *webhookRepository) ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	var subs []entity.WebhookSubscription
+	err := wr.db.ModelContext(ctx, &subs).Order("created_at DESC").Select()
+	return subs, err
+}
+
+func (wr *webhookRepository) GetActiveSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	var subs []entity.WebhookSubscription
+	err := wr.db.ModelContext(ctx, &subs).Where("active = TRUE").Select()
+	return subs, err
+}
+
+func (wr *webhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	_, err := wr.db.ModelContext(ctx, &entity.WebhookSubscription{}).Where("id = ?", id).Delete()
+	return err
+}
+
+func (wr *webhookRepository) CreateDeliveries(ctx context.Context, data []entity.WebhookDelivery) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := wr.db.ModelContext(ctx, &data).
+		OnConflict("(subscription_id, event_id) DO NOTHING").
+		Insert()
+	return err
+}
+
+func (wr *webhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	due := wr.db.ModelContext(ctx, &entity.WebhookDelivery{}).
+		Column("id").
+		Where("status = ?", entity.WebhookPending).
+		Where("next_attempt_at <= NOW()").
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+	_, err := wr.db.ModelContext(ctx, &deliveries).
+		Set("next_attempt_at = ?", time.Now().Add(lease)).
+		Where("id IN (?)", due).
+		Returning("*").
+		Update()
+	return deliveries, err
+}
+
+func (wr *webhookRepository) MarkDelivered(ctx context.Context, id string, attempts, responseStatus int) error {
+	_, err := wr.db.ModelContext(ctx, &entity.WebhookDelivery{}).
+		Set("status = ?", entity.WebhookDelivered).
+		Set("attempts = ?", attempts).
+		Set("response_status = ?", responseStatus).
+		Set("delivered_at = NOW()").
+		Set("last_error = NULL").
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (wr *webhookRepository) MarkRetry(ctx context.Context, id string, attempts, responseStatus int, next time.Time, lastError string) error {
+	_, err := wr.db.ModelContext(ctx, &entity.WebhookDelivery{}).
+		Set("attempts = ?", attempts).
+		Set("response_status = ?", responseStatus).
+		Set("next_attempt_at = ?", next).
+		Set("last_error = ?", lastError).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (wr *webhookRepository) MarkFailed(ctx context.Context, id string, attempts, responseStatus int, lastError string) error {
+	_, err := wr.db.ModelContext(ctx, &entity.WebhookDelivery{}).
+		Set("status = ?", entity.WebhookFailed).
+		Set("attempts = ?", attempts).
+		Set("response_status = ?", responseStatus).
+		Set("last_error = ?", lastError).
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (wr *webhookRepository) ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int, error) {
+	var deliveries []entity.WebhookDelivery
+	q := wr.db.ModelContext(ctx, &deliveries)
+	if filter.SubscriptionID != "" {
+		q = q.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	total, err := q.Order("created_at DESC").
+		Limit(filter.PageSize).
+		Offset((filter.Page - 1) * filter.PageSize).
+		SelectAndCount()
+	return deliveries, total, err
+}
+
+func (wr *webhookRepository) ReplayDelivery(ctx context.Context, id string) (bool, error) {
+	r, err := wr.db.ModelContext(ctx, &entity.WebhookDelivery{}).
+		Set("status = ?", entity.WebhookPending).
+		Set("attempts = 0").
+		Set("next_attempt_at = NOW()").
+		Set("delivered_at = NULL").
+		Where("id = ?", id).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return r.RowsAffected() > 0, nil
+}
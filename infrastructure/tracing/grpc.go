@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataCarrier cho phép propagator đọc/ghi traceparent trong gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	vals := metadata.MD(c).Get(key)
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func endRPCSpan(span trace.Span, err error) {
+	s := status.Convert(err)
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", s.Code().String()))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, s.Message())
+	}
+	span.End()
+}
+
+// UnaryServerInterceptor tạo span cho mỗi RPC, nối vào trace của caller nếu có traceparent
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md.Copy()))
+		ctx, span := Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("rpc.system", "grpc"), attribute.String("rpc.method", info.FullMethod)),
+		)
+		res, err := handler(ctx, req)
+		endRPCSpan(span, err)
+		return res, err
+	}
+}
+
+type clientConn struct {
+	grpc.ClientConnInterface
+}
+
+// WrapClientConn bọc kết nối tới service khác để mỗi lời gọi có span client và mang traceparent đi
+func WrapClientConn(cc grpc.ClientConnInterface) grpc.ClientConnInterface {
+	return &clientConn{ClientConnInterface: cc}
+}
+
+func (c *clientConn) inject(ctx context.Context, method string, kind trace.SpanKind) (context.Context, trace.Span) {
+	ctx, span := Start(ctx, method,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(attribute.String("rpc.system", "grpc"), attribute.String("rpc.method", method)),
+	)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+func (c *clientConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	ctx, span := c.inject(ctx, method, trace.SpanKindClient)
+	err := c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	endRPCSpan(span, err)
+	return err
+}
+
+func (c *clientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	// Span của stream chỉ bao phần mở stream, service hiện chỉ dùng unary
+	ctx, span := c.inject(ctx, method, trace.SpanKindClient)
+	s, err := c.ClientConnInterface.NewStream(ctx, desc, method, opts...)
+	endRPCSpan(span, err)
+	return s, err
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type spanKey struct{}
+
+// QueryHook tạo span cho mỗi truy vấn Postgres từ các repository
+type QueryHook struct{}
+
+var _ pg.QueryHook = QueryHook{}
+
+func (QueryHook) BeforeQuery(ctx context.Context, e *pg.QueryEvent) (context.Context, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attrs := []attribute.KeyValue{attribute.String("db.system", "postgresql")}
+	if q, err := e.UnformattedQuery(); err == nil {
+		attrs = append(attrs, attribute.String("db.statement", string(q)))
+	}
+	ctx, span := Start(ctx, "pg.query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	if e.Stash == nil {
+		e.Stash = make(map[any]any)
+	}
+	e.Stash[spanKey{}] = span
+	return ctx, nil
+}
+
+func (QueryHook) AfterQuery(_ context.Context, e *pg.QueryEvent) error {
+	span, ok := e.Stash[spanKey{}].(trace.Span)
+	if !ok {
+		return nil
+	}
+	if e.Result != nil {
+		span.SetAttributes(attribute.Int("db.rows_affected", e.Result.RowsAffected()))
+	}
+	err := e.Err
+	if errors.Is(err, pg.ErrNoRows) {
+		err = nil
+	}
+	End(span, err)
+	return nil
+}
@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"auth-service/domain/entity"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func newServer(t *testing.T, status int) (*httptest.Server, chan received) {
+	t.Helper()
+	ch := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, ch
+}
+
+func testDelivery() entity.WebhookDelivery {
+	return entity.WebhookDelivery{
+		ID:        "delivery-1",
+		EventType: entity.UserEventSuspended,
+		Payload:   []byte(`{"id":"event-1","type":"user.suspended"}`),
+	}
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	srv, ch := newServer(t, http.StatusNoContent)
+	sub := entity.WebhookSubscription{URL: srv.URL, Secret: "s3cret"}
+	delivery := testDelivery()
+
+	code, err := NewSender(srv.Client()).Send(context.Background(), sub, delivery)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Fatalf("code = %d, want %d", code, http.StatusNoContent)
+	}
+
+	got := <-ch
+	if string(got.body) != string(delivery.Payload) {
+		t.Fatalf("body = %s, want %s", got.body, delivery.Payload)
+	}
+	if v := got.header.Get(HeaderID); v != delivery.ID {
+		t.Errorf("%s = %q, want %q", HeaderID, v, delivery.ID)
+	}
+	if v := got.header.Get(HeaderEvent); v != string(delivery.EventType) {
+		t.Errorf("%s = %q, want %q", HeaderEvent, v, delivery.EventType)
+	}
+	if v := got.header.Get("Content-Type"); v != "application/json" {
+		t.Errorf("Content-Type = %q", v)
+	}
+	timestamp := got.header.Get(HeaderTimestamp)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(ts, 0)) > time.Minute {
+		t.Fatalf("%s = %q, want current unix seconds", HeaderTimestamp, timestamp)
+	}
+
+	// bên nhận tự tính lại chữ ký như hướng dẫn trong README
+	mac := hmac.New(sha256.New, []byte(sub.Secret))
+	mac.Write([]byte(timestamp + "." + string(got.body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if v := got.header.Get(HeaderSignature); !hmac.Equal([]byte(v), []byte(want)) {
+		t.Fatalf("%s = %q, want %q", HeaderSignature, v, want)
+	}
+}
+
+func TestSignDependsOnSecretAndTimestamp(t *testing.T) {
+	body := []byte(`{}`)
+	base := Sign("secret", "1700000000", body)
+	if Sign("other", "1700000000", body) == base {
+		t.Error("signature does not depend on the secret")
+	}
+	if Sign("secret", "1700000001", body) == base {
+		t.Error("signature does not depend on the timestamp")
+	}
+	if Sign("secret", "1700000000", []byte(`{ }`)) == base {
+		t.Error("signature does not depend on the body")
+	}
+}
+
+func TestSendNon2xxIsError(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusBadRequest, http.StatusServiceUnavailable} {
+		srv, _ := newServer(t, status)
+		code, err := NewSender(srv.Client()).Send(context.Background(), entity.WebhookSubscription{URL: srv.URL}, testDelivery())
+		if err == nil {
+			t.Errorf("status %d: want error", status)
+		}
+		if code != status {
+			t.Errorf("code = %d, want %d", code, status)
+		}
+	}
+}
+
+func TestSendConnectionErrorReturnsZero(t *testing.T) {
+	srv, _ := newServer(t, http.StatusOK)
+	srv.Close()
+	code, err := NewSender(nil).Send(context.Background(), entity.WebhookSubscription{URL: srv.URL}, testDelivery())
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if code != 0 {
+		t.Fatalf("code = %d, want 0", code)
+	}
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/anhvanhoa/service-core/domain/log"
+)
+
+type Group struct {
+	log    *log.LogGRPCImpl
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewGroup tạo nhóm worker có context riêng, không phụ thuộc request nên request kết thúc không hủy thao tác ghi
+func NewGroup(log *log.LogGRPCImpl) *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{
+		log:    log,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	g.mu.RLock()
+	if g.closed {
+		g.mu.RUnlock()
+		// Đang tắt service thì chạy đồng bộ để thao tác ghi không bị bỏ qua
+		g.run(name, fn)
+		return
+	}
+	g.wg.Add(1)
+	g.mu.RUnlock()
+	go func() {
+		defer g.wg.Done()
+		g.run(name, fn)
+	}()
+}
+
+func (g *Group) run(name string, fn func(ctx context.Context) error) {
+	if err := fn(g.ctx); err != nil {
+		g.log.Error(fmt.Sprintf("Background task %s failed: %s", name, err.Error()))
+	}
+}
+
+// Wait ngừng nhận tác vụ chạy nền mới và chờ các tác vụ đang chạy,
+// hết thời gian chờ thì hủy context của các tác vụ còn lại
+func (g *Group) Wait(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		return ctx.Err()
+	}
+}
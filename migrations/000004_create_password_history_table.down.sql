@@ -0,0 +1 @@
+DROP TABLE IF EXISTS password_history;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE
+    password_history (
+        id BIGSERIAL PRIMARY KEY,
+        user_id UUID NOT NULL,
+        password VARCHAR(255) NOT NULL,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX idx_password_history_user_id_created_at ON password_history (user_id, created_at DESC);
+
+INSERT INTO
+    password_history (user_id, password, created_at)
+SELECT
+    id,
+    password,
+    COALESCE(updated_at, created_at)
+FROM
+    users;
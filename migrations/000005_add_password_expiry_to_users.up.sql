@@ -0,0 +1,7 @@
+ALTER TABLE users
+ADD COLUMN password_changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+ADD COLUMN password_reset_required BOOLEAN DEFAULT FALSE;
+
+UPDATE users
+SET
+    password_changed_at = COALESCE(updated_at, created_at);
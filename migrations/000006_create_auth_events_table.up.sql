@@ -0,0 +1,16 @@
+CREATE TABLE
+    auth_events (
+        id BIGSERIAL PRIMARY KEY,
+        event_type VARCHAR(64) NOT NULL,
+        user_id UUID DEFAULT NULL,
+        ip VARCHAR(64),
+        user_agent TEXT,
+        os VARCHAR(255),
+        outcome VARCHAR(16) NOT NULL,
+        reason TEXT,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX idx_auth_events_user_id_created_at ON auth_events (user_id, created_at DESC);
+
+CREATE INDEX idx_auth_events_created_at ON auth_events (created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sessions_user_id_type;
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS ip,
+DROP COLUMN IF EXISTS user_agent;
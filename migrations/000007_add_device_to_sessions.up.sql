@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+ADD COLUMN ip VARCHAR(64),
+ADD COLUMN user_agent TEXT;
+
+CREATE INDEX idx_sessions_user_id_type ON sessions (user_id, type);
@@ -0,0 +1,16 @@
+CREATE TABLE
+    outbox (
+        id UUID PRIMARY KEY,
+        kind VARCHAR(16) NOT NULL,
+        topic VARCHAR(64) NOT NULL,
+        payload JSONB NOT NULL,
+        status VARCHAR(16) NOT NULL DEFAULT 'pending',
+        attempts INT NOT NULL DEFAULT 0,
+        task_id VARCHAR(255),
+        last_error TEXT,
+        next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        delivered_at TIMESTAMP,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX idx_outbox_status_next_attempt_at ON outbox (status, next_attempt_at);
@@ -0,0 +1,35 @@
+CREATE TABLE
+    webhook_subscriptions (
+        id UUID PRIMARY KEY,
+        url TEXT NOT NULL,
+        secret VARCHAR(255) NOT NULL,
+        event_types TEXT[] NOT NULL,
+        active BOOLEAN NOT NULL DEFAULT TRUE,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE TRIGGER update_webhook_subscriptions_updated_at BEFORE
+UPDATE ON webhook_subscriptions FOR EACH ROW EXECUTE FUNCTION update_updated_at_column ();
+
+CREATE TABLE
+    webhook_deliveries (
+        id UUID PRIMARY KEY,
+        subscription_id UUID NOT NULL,
+        event_id UUID NOT NULL,
+        event_type VARCHAR(64) NOT NULL,
+        payload JSONB NOT NULL,
+        status VARCHAR(16) NOT NULL DEFAULT 'pending',
+        attempts INT NOT NULL DEFAULT 0,
+        response_status INT,
+        last_error TEXT,
+        next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        delivered_at TIMESTAMP,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+        UNIQUE (subscription_id, event_id)
+    );
+
+CREATE INDEX idx_webhook_deliveries_status_next_attempt_at ON webhook_deliveries (status, next_attempt_at);
+
+CREATE INDEX idx_webhook_deliveries_subscription_id_created_at ON webhook_deliveries (subscription_id, created_at DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_unverified_registered_at;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS registered_at,
+DROP COLUMN IF EXISTS verify_reminder_sent_at;
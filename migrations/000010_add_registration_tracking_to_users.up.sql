@@ -0,0 +1,11 @@
+ALTER TABLE users
+ADD COLUMN registered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+ADD COLUMN verify_reminder_sent_at TIMESTAMP DEFAULT NULL;
+
+UPDATE users
+SET
+    registered_at = COALESCE(updated_at, created_at);
+
+CREATE INDEX idx_users_unverified_registered_at ON users (registered_at)
+WHERE
+    veryfied IS NULL;
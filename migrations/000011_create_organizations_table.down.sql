@@ -0,0 +1,6 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS active_organization_id;
+
+DROP TABLE IF EXISTS organization_members;
+
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,31 @@
+CREATE TABLE
+    organizations (
+        id UUID PRIMARY KEY,
+        name VARCHAR(255) NOT NULL,
+        slug VARCHAR(64) NOT NULL UNIQUE,
+        created_by UUID,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+    );
+
+CREATE TRIGGER update_organizations_updated_at BEFORE
+UPDATE ON organizations FOR EACH ROW EXECUTE FUNCTION update_updated_at_column ();
+
+CREATE INDEX idx_organizations_lower_name ON organizations (LOWER(name));
+
+CREATE TABLE
+    organization_members (
+        organization_id UUID NOT NULL,
+        user_id UUID NOT NULL,
+        role VARCHAR(16) NOT NULL DEFAULT 'member',
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+        PRIMARY KEY (organization_id, user_id)
+    );
+
+CREATE INDEX idx_organization_members_user_id ON organization_members (user_id);
+
+ALTER TABLE users
+ADD COLUMN active_organization_id UUID REFERENCES organizations (id) ON DELETE SET NULL;
@@ -0,0 +1,26 @@
+CREATE TABLE
+    invitations (
+        id UUID PRIMARY KEY,
+        email VARCHAR(255) NOT NULL,
+        token_hash VARCHAR(64) NOT NULL UNIQUE,
+        user_id UUID NOT NULL,
+        invited_by UUID,
+        organization_id UUID,
+        role VARCHAR(16),
+        expires_at TIMESTAMP NOT NULL,
+        accepted_at TIMESTAMP,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+        FOREIGN KEY (invited_by) REFERENCES users (id) ON DELETE SET NULL,
+        FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE
+    );
+
+CREATE TRIGGER update_invitations_updated_at BEFORE
+UPDATE ON invitations FOR EACH ROW EXECUTE FUNCTION update_updated_at_column ();
+
+CREATE INDEX idx_invitations_email ON invitations (email);
+
+CREATE INDEX idx_invitations_pending_expires_at ON invitations (expires_at)
+WHERE
+    accepted_at IS NULL;
@@ -0,0 +1,22 @@
+CREATE TABLE
+    personal_access_tokens (
+        id UUID PRIMARY KEY,
+        user_id UUID NOT NULL,
+        name VARCHAR(100) NOT NULL,
+        token_hash VARCHAR(64) NOT NULL UNIQUE,
+        prefix VARCHAR(16) NOT NULL,
+        scopes TEXT[] NOT NULL DEFAULT '{}',
+        expires_at TIMESTAMP,
+        last_used_at TIMESTAMP,
+        revoked_at TIMESTAMP,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE TRIGGER update_personal_access_tokens_updated_at BEFORE
+UPDATE ON personal_access_tokens FOR EACH ROW EXECUTE FUNCTION update_updated_at_column ();
+
+CREATE UNIQUE INDEX idx_personal_access_tokens_user_name ON personal_access_tokens (user_id, name)
+WHERE
+    revoked_at IS NULL;
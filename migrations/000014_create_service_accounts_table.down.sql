@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS service_account_keys;
+
+DROP TABLE IF EXISTS service_accounts;
@@ -0,0 +1,43 @@
+CREATE TABLE
+    service_accounts (
+        id UUID PRIMARY KEY,
+        name VARCHAR(100) NOT NULL,
+        description TEXT,
+        owner_user_id UUID,
+        owner_organization_id UUID,
+        created_by UUID,
+        disabled_at TIMESTAMP,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (owner_user_id) REFERENCES users (id) ON DELETE CASCADE,
+        FOREIGN KEY (owner_organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+        FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL,
+        CHECK ((owner_user_id IS NULL) <> (owner_organization_id IS NULL))
+    );
+
+CREATE TRIGGER update_service_accounts_updated_at BEFORE
+UPDATE ON service_accounts FOR EACH ROW EXECUTE FUNCTION update_updated_at_column ();
+
+CREATE INDEX idx_service_accounts_owner_user_id ON service_accounts (owner_user_id);
+
+CREATE INDEX idx_service_accounts_owner_organization_id ON service_accounts (owner_organization_id);
+
+CREATE TABLE
+    service_account_keys (
+        id UUID PRIMARY KEY,
+        service_account_id UUID NOT NULL,
+        type VARCHAR(16) NOT NULL,
+        secret_hash VARCHAR(64),
+        public_key TEXT,
+        expires_at TIMESTAMP,
+        last_used_at TIMESTAMP,
+        revoked_at TIMESTAMP,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (service_account_id) REFERENCES service_accounts (id) ON DELETE CASCADE
+    );
+
+CREATE TRIGGER update_service_account_keys_updated_at BEFORE
+UPDATE ON service_account_keys FOR EACH ROW EXECUTE FUNCTION update_updated_at_column ();
+
+CREATE INDEX idx_service_account_keys_service_account_id ON service_account_keys (service_account_id);
@@ -0,0 +1,7 @@
+DELETE FROM users
+WHERE
+    password IS NULL;
+
+ALTER TABLE users
+ALTER COLUMN password
+SET NOT NULL;
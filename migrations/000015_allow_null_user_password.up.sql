@@ -0,0 +1,4 @@
+-- Tài khoản tạo sẵn cho lời mời chưa có mật khẩu cho tới khi người được mời chấp nhận
+ALTER TABLE users
+ALTER COLUMN password
+DROP NOT NULL;
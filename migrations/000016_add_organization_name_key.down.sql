@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_organizations_name_key;
+
+ALTER TABLE organizations
+DROP COLUMN IF EXISTS name_key;
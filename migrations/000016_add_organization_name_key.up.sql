@@ -0,0 +1,14 @@
+-- name_key do ứng dụng ghi theo organization.name_scope: LOWER(name) khi global, created_by/LOWER(name) khi owner.
+-- Tổ chức cũ nhận key riêng theo id, trùng tên với chúng vẫn do NameExists phát hiện.
+ALTER TABLE organizations
+ADD COLUMN name_key VARCHAR(300);
+
+UPDATE organizations
+SET
+    name_key = 'legacy:' || id::text;
+
+ALTER TABLE organizations
+ALTER COLUMN name_key
+SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_organizations_name_key ON organizations (name_key);
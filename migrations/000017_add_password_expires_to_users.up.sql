@@ -0,0 +1,3 @@
+-- Hết hạn mật khẩu chỉ áp dụng cho tài khoản được bật (tài khoản quản trị), bật bằng admin password-expiry
+ALTER TABLE users
+ADD COLUMN password_expires BOOLEAN NOT NULL DEFAULT FALSE;